@@ -4,10 +4,50 @@ import "github.com/gofiber/fiber/v2"
 
 type Controller interface {
 	Login(c *fiber.Ctx) error
+	VerifyTwoFactor(c *fiber.Ctx) error
+	SetupTwoFactor(c *fiber.Ctx) error
+	EnableTwoFactor(c *fiber.Ctx) error
+	DisableTwoFactor(c *fiber.Ctx) error
+	RefreshToken(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+	GetJWKS(c *fiber.Ctx) error
+	GetAdmins(c *fiber.Ctx) error
+	AddAdmin(c *fiber.Ctx) error
+	DisableAdmin(c *fiber.Ctx) error
+	EnableAdmin(c *fiber.Ctx) error
+	DeleteAdmin(c *fiber.Ctx) error
+	ChangePassword(c *fiber.Ctx) error
+	ResetAdminPassword(c *fiber.Ctx) error
 	AddProduct(c *fiber.Ctx) error
+	GetProduct(c *fiber.Ctx) error
 	GetProducts(c *fiber.Ctx) error
+	GetProductTrash(c *fiber.Ctx) error
+	RestoreProduct(c *fiber.Ctx) error
+	DeleteProductImage(c *fiber.Ctx) error
+	GetStockMovements(c *fiber.Ctx) error
+	AddStockMovement(c *fiber.Ctx) error
 	DeleteProduct(c *fiber.Ctx) error
 	UpdateProduct(c *fiber.Ctx) error
+	AddProductVariant(c *fiber.Ctx) error
+	UpdateProductVariant(c *fiber.Ctx) error
+	DeleteProductVariant(c *fiber.Ctx) error
+	AddOptionGroup(c *fiber.Ctx) error
+	UpdateOptionGroup(c *fiber.Ctx) error
+	DeleteOptionGroup(c *fiber.Ctx) error
+	AddProductOption(c *fiber.Ctx) error
+	UpdateProductOption(c *fiber.Ctx) error
+	DeleteProductOption(c *fiber.Ctx) error
+	GetCategories(c *fiber.Ctx) error
+	GetCategory(c *fiber.Ctx) error
+	AddCategory(c *fiber.Ctx) error
+	UpdateCategory(c *fiber.Ctx) error
+	DeleteCategory(c *fiber.Ctx) error
+	GetPackages(c *fiber.Ctx) error
+	GetPackage(c *fiber.Ctx) error
+	AddPackage(c *fiber.Ctx) error
+	UpdatePackage(c *fiber.Ctx) error
+	DeletePackage(c *fiber.Ctx) error
+	GetOrder(c *fiber.Ctx) error
 	GetOrders(c *fiber.Ctx) error
 	UpdateOrder(c *fiber.Ctx) error
 	DeleteOrder(c *fiber.Ctx) error
@@ -3,25 +3,38 @@ package controller
 import (
 	"catering-admin-go/domain"
 	"catering-admin-go/helper"
+	"catering-admin-go/logger"
 	"catering-admin-go/service"
 	"catering-admin-go/web"
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"mime/multipart"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type ControllerImpl struct {
-	svc service.Service
+	svc         service.Service
+	userLimiter *helper.LoginLimiter
+	ipLimiter   *helper.LoginLimiter
 }
 
 func NewControllerImpl(svc service.Service) Controller {
-	return &ControllerImpl{svc: svc}
+	return &ControllerImpl{
+		svc:         svc,
+		userLimiter: helper.NewLoginLimiter(5, time.Minute, time.Hour, 15*time.Minute),
+		ipLimiter:   helper.NewLoginLimiter(20, time.Minute, time.Hour, 15*time.Minute),
+	}
 }
 
 func (ctrl *ControllerImpl) Login(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var reqBody domain.Admin
@@ -31,15 +44,368 @@ func (ctrl *ControllerImpl) Login(c *fiber.Ctx) error {
 	if err := helper.ValidateStruct(reqBody); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Please fill all required fields correctly."})
 	}
+
+	userKey := strings.ToLower(reqBody.Username)
+	ip := c.IP()
+	if wait := max(ctrl.userLimiter.Check(userKey), ctrl.ipLimiter.Check(ip)); wait > 0 {
+		return tooManyLoginAttempts(c, wait)
+	}
+
 	result, err := ctrl.svc.Login(ctx, &reqBody)
 	if err != nil {
+		// Only wrong credentials count towards the lockout; a database outage
+		// must not lock admins out.
+		if err != service.ErrInvalidCredentials {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Unable to log in. Please try again later."})
+		}
+		if wait := ctrl.recordLoginFailure(userKey, ip); wait > 0 {
+			return tooManyLoginAttempts(c, wait)
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Incorrect username or password."})
 	}
+
+	ctrl.userLimiter.Reset(userKey)
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (ctrl *ControllerImpl) VerifyTwoFactor(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.TwoFactorLoginRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Request data is invalid."})
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Please enter your authentication code."})
+	}
+
+	// Code guesses are counted per admin, so logging in again for a fresh
+	// challenge does not buy more guesses.
+	adminId, err := helper.ParseChallengeToken(reqBody.ChallengeToken)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Login session expired. Please log in again."})
+	}
+	challengeKey := "2fa:" + adminId.String()
+	ip := c.IP()
+	if wait := max(ctrl.userLimiter.Check(challengeKey), ctrl.ipLimiter.Check(ip)); wait > 0 {
+		return tooManyLoginAttempts(c, wait)
+	}
+
+	result, err := ctrl.svc.VerifyTwoFactor(ctx, &reqBody)
+	if err != nil {
+		if err == service.ErrInvalidTwoFactorCode {
+			if wait := ctrl.recordLoginFailure(challengeKey, ip); wait > 0 {
+				return tooManyLoginAttempts(c, wait)
+			}
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Incorrect authentication code."})
+		}
+		if err == service.ErrInvalidCredentials {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Login session expired. Please log in again."})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Unable to verify code. Please try again later."})
+	}
+
+	ctrl.userLimiter.Reset(challengeKey)
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (ctrl *ControllerImpl) SetupTwoFactor(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.SetupTwoFactor(ctx)
+	if err != nil {
+		return twoFactorErrorResponse(c, err, "Unable to set up two-factor authentication. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Scan the QR code with your authenticator app.", result)
+}
+
+func (ctrl *ControllerImpl) EnableTwoFactor(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.TwoFactorCodeRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	result, err := ctrl.svc.EnableTwoFactor(ctx, &reqBody)
+	if err != nil {
+		return twoFactorErrorResponse(c, err, "Unable to enable two-factor authentication. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Two-factor authentication enabled. Store your recovery codes somewhere safe.", result)
+}
+
+func (ctrl *ControllerImpl) DisableTwoFactor(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.DisableTwoFactorRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	if err := ctrl.svc.DisableTwoFactor(ctx, &reqBody); err != nil {
+		return twoFactorErrorResponse(c, err, "Unable to disable two-factor authentication. Please try again later.")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Two-factor authentication disabled.", nil)
+}
+
+func (ctrl *ControllerImpl) recordLoginFailure(username string, ip string) time.Duration {
+	userLockout := ctrl.userLimiter.Fail(username)
+	if userLockout > 0 {
+		logger.GetLogger("security-log").Log("login lockout", "warn", fmt.Sprintf("username %q locked out for %s after repeated failed logins from %s", username, userLockout, ip))
+	}
+
+	ipLockout := ctrl.ipLimiter.Fail(ip)
+	if ipLockout > 0 {
+		logger.GetLogger("security-log").Log("login lockout", "warn", fmt.Sprintf("ip %s locked out for %s after repeated failed logins", ip, ipLockout))
+	}
+
+	return max(userLockout, ipLockout)
+}
+
+func tooManyLoginAttempts(c *fiber.Ctx, wait time.Duration) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"message": "Too many failed login attempts. Please try again later."})
+}
+
+func (ctrl *ControllerImpl) RefreshToken(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.RefreshTokenRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Request data is invalid."})
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Refresh token is required."})
+	}
+	result, err := ctrl.svc.RefreshToken(ctx, reqBody.RefreshToken)
+	if err != nil {
+		if err == service.ErrInvalidRefreshToken || err == service.ErrRefreshTokenReused {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Session expired. Please log in again."})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Unable to refresh session. Please try again later."})
+	}
 	return c.Status(fiber.StatusOK).JSON(result)
 }
 
+func (ctrl *ControllerImpl) Logout(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.LogoutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&reqBody); err != nil {
+			return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+		}
+	}
+
+	if err := ctrl.svc.Logout(ctx, reqBody.RefreshToken); err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Unable to log out. Please try again later.", "")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Successfully logged out.", nil)
+}
+
+func (ctrl *ControllerImpl) GetJWKS(c *fiber.Ctx) error {
+	keys, err := helper.Keys()
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Signing keys are not available.", "")
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"keys": keys.JWKS()})
+}
+
+func (ctrl *ControllerImpl) GetAdmins(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	admins, err := ctrl.svc.GetAdmins(ctx)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load admins. Please try again later.", "")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Admins loaded successfully.", web.ToAdminAccountResponses(admins))
+}
+
+func (ctrl *ControllerImpl) AddAdmin(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.CreateAdminRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	result, err := ctrl.svc.AddAdmin(ctx, &reqBody)
+	if err != nil {
+		if err == service.ErrUsernameTaken {
+			return web.ErrorResponse(c, fiber.StatusConflict, "Username is already taken.", "")
+		}
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Unable to add admin. Please try again later.", "")
+	}
+	return web.SuccessResponse(c, fiber.StatusCreated, "Admin successfully added.", web.ToAdminAccountResponse(result))
+}
+
+func (ctrl *ControllerImpl) DisableAdmin(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Admin id is invalid.", "")
+	}
+	if err := ctrl.svc.DisableAdmin(ctx, id); err != nil {
+		return adminErrorResponse(c, err, "Unable to disable admin. Please try again later.")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Admin successfully disabled.", nil)
+}
+
+func (ctrl *ControllerImpl) EnableAdmin(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Admin id is invalid.", "")
+	}
+	if err := ctrl.svc.EnableAdmin(ctx, id); err != nil {
+		return adminErrorResponse(c, err, "Unable to enable admin. Please try again later.")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Admin successfully enabled.", nil)
+}
+
+func (ctrl *ControllerImpl) DeleteAdmin(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Admin id is invalid.", "")
+	}
+	if err := ctrl.svc.DeleteAdmin(ctx, id); err != nil {
+		return adminErrorResponse(c, err, "Unable to delete admin. Please try again later.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (ctrl *ControllerImpl) ChangePassword(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.ChangePasswordRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	if err := ctrl.svc.ChangePassword(ctx, &reqBody); err != nil {
+		if err == service.ErrIncorrectPassword {
+			return web.ErrorResponse(c, fiber.StatusBadRequest, "Current password is incorrect.", "")
+		}
+		return adminErrorResponse(c, err, "Unable to change password. Please try again later.")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Password successfully changed.", nil)
+}
+
+func (ctrl *ControllerImpl) ResetAdminPassword(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Admin id is invalid.", "")
+	}
+	var reqBody web.ResetPasswordRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	if err := ctrl.svc.ResetAdminPassword(ctx, id, &reqBody); err != nil {
+		return adminErrorResponse(c, err, "Unable to reset password. Please try again later.")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Password successfully reset.", nil)
+}
+
+func twoFactorErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch err {
+	case service.ErrInvalidTwoFactorCode:
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Incorrect authentication code.", "")
+	case service.ErrIncorrectPassword:
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Current password is incorrect.", "")
+	case service.ErrTwoFactorAlreadyEnabled:
+		return web.ErrorResponse(c, fiber.StatusConflict, "Two-factor authentication is already enabled.", "")
+	case service.ErrTwoFactorNotSetUp:
+		return web.ErrorResponse(c, fiber.StatusConflict, "Set up two-factor authentication before enabling it.", "")
+	case service.ErrTwoFactorNotEnabled:
+		return web.ErrorResponse(c, fiber.StatusConflict, "Two-factor authentication is not enabled.", "")
+	default:
+		return adminErrorResponse(c, err, message)
+	}
+}
+
+func adminErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch err {
+	case service.ErrAdminNotFound:
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Admin not found.", "")
+	case service.ErrCannotModifySelf:
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "You cannot disable or delete your own account.", "")
+	case service.ErrUnauthenticated:
+		return web.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", "")
+	default:
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, message, "")
+	}
+}
+
+// serviceError hands domain errors to web.ErrorHandler untouched and hides
+// anything else behind a 500 carrying message.
+func serviceError(err error, message string) error {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, message)
+}
+
+// formImages returns the files uploaded under "images" when the body is a
+// multipart form.
+func formImages(c *fiber.Ctx) []*multipart.FileHeader {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return nil
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil
+	}
+	return form.File["images"]
+}
+
+// ifMatchVersion reads the version a write was based on from If-Match, so a
+// client can't overwrite changes it has not seen.
+func ifMatchVersion(c *fiber.Ctx) (int, error) {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		return 0, fiber.NewError(fiber.StatusPreconditionRequired, "If-Match header is required. Send the ETag you last received.")
+	}
+	version, err := web.ParseETag(header)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusPreconditionFailed, "If-Match does not match the current version.")
+	}
+	return version, nil
+}
+
 func (ctrl *ControllerImpl) AddProduct(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var reqBody web.Request
@@ -55,104 +421,546 @@ func (ctrl *ControllerImpl) AddProduct(c *fiber.Ctx) error {
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "Stock must be a valid number.", "")
 	}
 	reqBody.Stock = stock
+	if raw := c.FormValue("category_id"); raw != "" {
+		categoryId, err := strconv.Atoi(raw)
+		if err != nil {
+			return web.ErrorResponse(c, fiber.StatusBadRequest, "Category must be a valid number.", "")
+		}
+		reqBody.CategoryId = &categoryId
+	}
 	if err := helper.ValidateStruct(reqBody); err != nil {
-		return web.ErrorResponse(c, fiber.StatusBadRequest, "Please complete all required product fields.", "")
+		return err
 	}
-	result, err := ctrl.svc.AddProduct(ctx, &reqBody)
+	result, err := ctrl.svc.AddProduct(ctx, &reqBody, formImages(c))
 	if err != nil {
-		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Unable to add product. Please try again later.", "")
+		return serviceError(err, "Unable to add product. Please try again later.")
 	}
+	c.Set(fiber.HeaderETag, web.ETag(result.Version))
 	return web.SuccessResponse[*domain.Domain](c, fiber.StatusCreated, "Product successfully added.", result)
 }
 
+func (ctrl *ControllerImpl) GetProduct(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	product, err := ctrl.svc.GetProduct(ctx, c.Params("id"))
+	if err != nil {
+		return serviceError(err, "Failed to load product. Please try again later.")
+	}
+	c.Set(fiber.HeaderETag, web.ETag(product.Version))
+	return web.SuccessResponse(c, fiber.StatusOK, "Product loaded successfully.", product)
+}
+
 func (ctrl *ControllerImpl) GetProducts(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	return ctrl.listProducts(c, false)
+}
+
+// GetProductTrash lists deleted products that can still be restored.
+func (ctrl *ControllerImpl) GetProductTrash(c *fiber.Ctx) error {
+	return ctrl.listProducts(c, true)
+}
+
+func (ctrl *ControllerImpl) listProducts(c *fiber.Ctx, deleted bool) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	products, err := ctrl.svc.GetProducts(ctx)
+	var query web.ProductQuery
+	if err := c.QueryParser(&query); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Query parameters are invalid.", "")
+	}
+	if err := helper.ValidateStruct(query); err != nil {
+		return err
+	}
+	filter, err := query.ToFilter()
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Query parameters are invalid.", err.Error())
+	}
+	filter.Deleted = deleted
+
+	products, meta, err := ctrl.svc.GetProducts(ctx, filter)
 	if err != nil {
-		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load products. Please try again later.", "")
+		return serviceError(err, "Failed to load products. Please try again later.")
 	}
-	return web.SuccessResponse[[]*domain.Domain](c, fiber.StatusOK, "Products loaded successfully.", products)
+	return web.SuccessResponseWithMeta[[]*domain.Domain](c, fiber.StatusOK, "Products loaded successfully.", products, meta)
 }
 
 func (ctrl *ControllerImpl) DeleteProduct(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
 	id := c.Params("id")
-	err := ctrl.svc.DeleteProduct(ctx, id)
+	err = ctrl.svc.DeleteProduct(ctx, id, version)
 	if err != nil {
-		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Unable to delete product. Please try again later.", "")
+		return serviceError(err, "Unable to delete product. Please try again later.")
 	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// UpdateProduct only changes the fields present in the body, which may be
+// JSON or a multipart/urlencoded form. Images sent in a multipart body are
+// added to the product's existing ones.
 func (ctrl *ControllerImpl) UpdateProduct(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
 	id := c.Params("id")
-	name := c.FormValue("name")
-	description := c.FormValue("description")
-	stockStr := c.FormValue("stock")
-	priceStr := c.FormValue("price")
-	stock, err := strconv.Atoi(stockStr)
+	reqBody := new(web.UpdateProductRequest)
+	if err := c.BodyParser(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	images := formImages(c)
+	if reqBody.Empty() && len(images) == 0 {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Provide at least one field to update.", "")
+	}
+	response, err := ctrl.svc.UpdateProduct(ctx, reqBody, images, id, version)
 	if err != nil {
-		return web.ErrorResponse(c, fiber.StatusBadRequest, "Stock must be a valid number.", "")
+		return serviceError(err, "Failed to update product. Please try again later.")
+	}
+	c.Set(fiber.HeaderETag, web.ETag(response.Version))
+	return web.SuccessResponse(c, fiber.StatusOK, "Product successfully updated.", response)
+}
+
+func (ctrl *ControllerImpl) GetStockMovements(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var query web.StockMovementQuery
+	if err := c.QueryParser(&query); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Query parameters are invalid.", "")
 	}
-	price, err := strconv.Atoi(priceStr)
+	if err := helper.ValidateStruct(query); err != nil {
+		return err
+	}
+	filter, err := query.ToFilter(c.Params("id"))
 	if err != nil {
-		return web.ErrorResponse(c, fiber.StatusBadRequest, "Price must be a valid number.", "")
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Query parameters are invalid.", err.Error())
 	}
-	reqBody := &web.Request{
-		Name:        name,
-		Description: description,
-		Stock:       stock,
-		Price:       price,
+
+	movements, meta, err := ctrl.svc.GetStockMovements(ctx, filter)
+	if err != nil {
+		return serviceError(err, "Failed to load stock movements. Please try again later.")
 	}
-	response, err := ctrl.svc.UpdateProduct(ctx, reqBody, id)
+	return web.SuccessResponseWithMeta(c, fiber.StatusOK, "Stock movements loaded successfully.", movements, meta)
+}
+
+func (ctrl *ControllerImpl) AddStockMovement(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.StockMovementRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	movement, err := ctrl.svc.AddStockMovement(ctx, c.Params("id"), &reqBody)
 	if err != nil {
-		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update product. Please try again later.", "")
+		return serviceError(err, "Unable to record stock movement. Please try again later.")
 	}
-	return web.SuccessResponse(c, fiber.StatusOK, "Product successfully updated.", response)
+	return web.SuccessResponse(c, fiber.StatusCreated, "Stock movement successfully recorded.", movement)
+}
+
+func (ctrl *ControllerImpl) DeleteProductImage(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.DeleteProductImage(ctx, c.Params("id"), c.Params("imageId")); err != nil {
+		return serviceError(err, "Failed to delete image. Please try again later.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (ctrl *ControllerImpl) RestoreProduct(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	product, err := ctrl.svc.RestoreProduct(ctx, c.Params("id"))
+	if err != nil {
+		return serviceError(err, "Failed to restore product. Please try again later.")
+	}
+	c.Set(fiber.HeaderETag, web.ETag(product.Version))
+	return web.SuccessResponse(c, fiber.StatusOK, "Product successfully restored.", product)
+}
+
+func (ctrl *ControllerImpl) AddProductVariant(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.VariantRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	variant, err := ctrl.svc.AddProductVariant(ctx, c.Params("id"), &reqBody)
+	if err != nil {
+		return serviceError(err, "Unable to add variant. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusCreated, "Variant successfully added.", variant)
+}
+
+func (ctrl *ControllerImpl) UpdateProductVariant(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.VariantRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	variant, err := ctrl.svc.UpdateProductVariant(ctx, c.Params("id"), c.Params("variantId"), &reqBody)
+	if err != nil {
+		return serviceError(err, "Failed to update variant. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Variant successfully updated.", variant)
+}
+
+func (ctrl *ControllerImpl) DeleteProductVariant(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.DeleteProductVariant(ctx, c.Params("id"), c.Params("variantId")); err != nil {
+		return serviceError(err, "Unable to delete variant. Please try again later.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (ctrl *ControllerImpl) AddOptionGroup(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.OptionGroupRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	group, err := ctrl.svc.AddOptionGroup(ctx, c.Params("id"), &reqBody)
+	if err != nil {
+		return serviceError(err, "Unable to add option group. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusCreated, "Option group successfully added.", group)
+}
+
+func (ctrl *ControllerImpl) UpdateOptionGroup(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.OptionGroupRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	group, err := ctrl.svc.UpdateOptionGroup(ctx, c.Params("id"), c.Params("groupId"), &reqBody)
+	if err != nil {
+		return serviceError(err, "Failed to update option group. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Option group successfully updated.", group)
+}
+
+func (ctrl *ControllerImpl) DeleteOptionGroup(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.DeleteOptionGroup(ctx, c.Params("id"), c.Params("groupId")); err != nil {
+		return serviceError(err, "Unable to delete option group. Please try again later.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (ctrl *ControllerImpl) AddProductOption(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.OptionRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	option, err := ctrl.svc.AddProductOption(ctx, c.Params("id"), c.Params("groupId"), &reqBody)
+	if err != nil {
+		return serviceError(err, "Unable to add option. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusCreated, "Option successfully added.", option)
+}
+
+func (ctrl *ControllerImpl) UpdateProductOption(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.OptionRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	option, err := ctrl.svc.UpdateProductOption(ctx, c.Params("id"), c.Params("groupId"), c.Params("optionId"), &reqBody)
+	if err != nil {
+		return serviceError(err, "Failed to update option. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Option successfully updated.", option)
+}
+
+func (ctrl *ControllerImpl) DeleteProductOption(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.DeleteProductOption(ctx, c.Params("id"), c.Params("groupId"), c.Params("optionId")); err != nil {
+		return serviceError(err, "Unable to delete option. Please try again later.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// categoryId reads the :id route parameter of the category endpoints.
+func categoryId(c *fiber.Ctx) (int, error) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id < 1 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Category id is invalid.")
+	}
+	return id, nil
+}
+
+func (ctrl *ControllerImpl) GetCategories(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	categories, err := ctrl.svc.GetCategories(ctx)
+	if err != nil {
+		return serviceError(err, "Failed to load categories. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Categories loaded successfully.", categories)
+}
+
+func (ctrl *ControllerImpl) GetCategory(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	id, err := categoryId(c)
+	if err != nil {
+		return err
+	}
+	category, err := ctrl.svc.GetCategory(ctx, id)
+	if err != nil {
+		return serviceError(err, "Failed to load category. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Category loaded successfully.", category)
+}
+
+func (ctrl *ControllerImpl) AddCategory(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.CategoryRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	category, err := ctrl.svc.AddCategory(ctx, &reqBody)
+	if err != nil {
+		return serviceError(err, "Unable to add category. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusCreated, "Category successfully added.", category)
+}
+
+func (ctrl *ControllerImpl) UpdateCategory(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	id, err := categoryId(c)
+	if err != nil {
+		return err
+	}
+	var reqBody web.CategoryRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	category, err := ctrl.svc.UpdateCategory(ctx, &reqBody, id)
+	if err != nil {
+		return serviceError(err, "Failed to update category. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Category successfully updated.", category)
+}
+
+func (ctrl *ControllerImpl) DeleteCategory(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	id, err := categoryId(c)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.svc.DeleteCategory(ctx, id); err != nil {
+		return serviceError(err, "Unable to delete category. Please try again later.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (ctrl *ControllerImpl) GetPackages(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	packages, err := ctrl.svc.GetPackages(ctx)
+	if err != nil {
+		return serviceError(err, "Failed to load packages. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Packages loaded successfully.", packages)
+}
+
+func (ctrl *ControllerImpl) GetPackage(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	pkg, err := ctrl.svc.GetPackage(ctx, c.Params("id"))
+	if err != nil {
+		return serviceError(err, "Failed to load package. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Package loaded successfully.", pkg)
+}
+
+func (ctrl *ControllerImpl) AddPackage(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.PackageRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	pkg, err := ctrl.svc.AddPackage(ctx, &reqBody)
+	if err != nil {
+		return serviceError(err, "Unable to add package. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusCreated, "Package successfully added.", pkg)
+}
+
+func (ctrl *ControllerImpl) UpdatePackage(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.PackageRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return err
+	}
+	pkg, err := ctrl.svc.UpdatePackage(ctx, c.Params("id"), &reqBody)
+	if err != nil {
+		return serviceError(err, "Failed to update package. Please try again later.")
+	}
+	return web.SuccessResponse(c, fiber.StatusOK, "Package successfully updated.", pkg)
+}
+
+func (ctrl *ControllerImpl) DeletePackage(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.DeletePackage(ctx, c.Params("id")); err != nil {
+		return serviceError(err, "Unable to delete package. Please try again later.")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (ctrl *ControllerImpl) GetOrder(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	order, err := ctrl.svc.GetOrder(ctx, c.Params("id"))
+	if err != nil {
+		return serviceError(err, "Failed to load order. Please try again later.")
+	}
+	c.Set(fiber.HeaderETag, web.ETag(order.Version))
+	return web.SuccessResponse(c, fiber.StatusOK, "Order loaded successfully.", order)
 }
 
 func (ctrl *ControllerImpl) GetOrders(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	orders, err := ctrl.svc.GetOrders(ctx)
+	var query web.OrderQuery
+	if err := c.QueryParser(&query); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Query parameters are invalid.", "")
+	}
+	if err := helper.ValidateStruct(query); err != nil {
+		return err
+	}
+	filter, err := query.ToFilter()
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Query parameters are invalid.", err.Error())
+	}
+
+	orders, meta, err := ctrl.svc.GetOrders(ctx, filter)
 	if err != nil {
-		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to load orders. Please try again later.", "")
+		return serviceError(err, "Failed to load orders. Please try again later.")
 	}
-	return web.SuccessResponse[[]*domain.Orders](c, fiber.StatusOK, "Orders loaded successfully.", orders)
+	return web.SuccessResponseWithMeta[[]*domain.Orders](c, fiber.StatusOK, "Orders loaded successfully.", orders, meta)
 }
 
 func (ctrl *ControllerImpl) UpdateOrder(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
 	var reqBody domain.Orders
-	err := c.BodyParser(&reqBody)
+	err = c.BodyParser(&reqBody)
 	if err != nil {
-		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update order. Please try again later.", "")
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Request data is invalid.", "")
+	}
+	// Only the status and its note are read from the body.
+	if err := helper.Validator().StructPartial(reqBody, "Note"); err != nil {
+		return err
 	}
 	id := c.Params("id")
 
-	if err := ctrl.svc.UpdateOrder(ctx, &reqBody, id); err != nil {
-		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update order. Please try again later.", "")
+	if err := ctrl.svc.UpdateOrder(ctx, &reqBody, id, version); err != nil {
+		return serviceError(err, "Failed to update order. Please try again later.")
 	}
+	c.Set(fiber.HeaderETag, web.ETag(version+1))
 	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Order successfully updated.", nil)
 }
 
 func (ctrl *ControllerImpl) DeleteOrder(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
 	id := c.Params("id")
-	if err := ctrl.svc.DeleteOrder(ctx, id); err != nil {
-		return web.ErrorResponse(c, fiber.StatusBadRequest, "Failed to delete order", "")
+	if err := ctrl.svc.DeleteOrder(ctx, id, version); err != nil {
+		return serviceError(err, "Failed to delete order. Please try again later.")
 	}
 	return web.SuccessResponse[interface{}](c, fiber.StatusNoContent, "Order successfully deleted", nil)
 }
@@ -3,14 +3,22 @@ package controller
 import (
 	"bytes"
 	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"catering-admin-go/service"
 	"catering-admin-go/service/mocks"
 	"catering-admin-go/web"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -77,6 +85,679 @@ func TestLogin(t *testing.T) {
 	}
 }
 
+func TestLoginLockout(t *testing.T) {
+	svc := mocks.NewService(t)
+	svc.On("Login", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidCredentials).Times(5)
+	ctrl := NewControllerImpl(svc)
+
+	app := fiber.New()
+	app.Post("/v1/login", ctrl.Login)
+
+	jsonBytes, _ := json.Marshal(&domain.Admin{Username: "admin", Password: "wrong"})
+	var statuses []int
+	var retryAfter string
+	for i := 0; i < 6; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/v1/login", bytes.NewReader(jsonBytes))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses = append(statuses, resp.StatusCode)
+		retryAfter = resp.Header.Get(fiber.HeaderRetryAfter)
+	}
+
+	assert.Equal(t, []int{401, 401, 401, 401, 429, 429}, statuses)
+	assert.Equal(t, "60", retryAfter)
+}
+
+func TestVerifyTwoFactorLockoutSurvivesNewChallenges(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	adminId := uuid.New()
+	svc := mocks.NewService(t)
+	svc.On("Login", mock.Anything, mock.Anything).Return(func(context.Context, *domain.Admin) *web.AdminResponse {
+		challenge, err := helper.GenerateChallengeToken(adminId)
+		assert.NoError(t, err)
+		return &web.AdminResponse{Username: "admin", TwoFactorRequired: true, ChallengeToken: challenge}
+	}, nil)
+	svc.On("VerifyTwoFactor", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidTwoFactorCode).Times(5)
+	ctrl := NewControllerImpl(svc)
+
+	app := fiber.New()
+	app.Post("/v1/login", ctrl.Login)
+	app.Post("/v1/login/2fa", ctrl.VerifyTwoFactor)
+
+	post := func(path string, body interface{}) *http.Response {
+		jsonBytes, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(jsonBytes))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	var statuses []int
+	for i := 0; i < 6; i++ {
+		// The right password hands out a fresh challenge every time.
+		resp := post("/v1/login", &domain.Admin{Username: "admin", Password: "admin123"})
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		var login web.AdminResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&login))
+
+		resp = post("/v1/login/2fa", &web.TwoFactorLoginRequest{ChallengeToken: login.ChallengeToken, Code: "000000"})
+		statuses = append(statuses, resp.StatusCode)
+	}
+
+	assert.Equal(t, []int{401, 401, 401, 401, 429, 429}, statuses)
+}
+
+func TestLoginServiceFailure(t *testing.T) {
+	svc := mocks.NewService(t)
+	svc.On("Login", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Times(6)
+	ctrl := NewControllerImpl(svc)
+
+	app := fiber.New()
+	app.Post("/v1/login", ctrl.Login)
+
+	jsonBytes, _ := json.Marshal(&domain.Admin{Username: "admin", Password: "admin123"})
+	var statuses []int
+	for i := 0; i < 6; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/v1/login", bytes.NewReader(jsonBytes))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses = append(statuses, resp.StatusCode)
+	}
+
+	// Outages are not failed attempts, so they never lead to a lockout.
+	assert.Equal(t, []int{500, 500, 500, 500, 500, 500}, statuses)
+}
+
+func TestGetAdmins(t *testing.T) {
+	svc := mocks.NewService(t)
+	svc.On("GetAdmins", mock.Anything).Return([]*domain.Admin{
+		{
+			Id:       uuid.New(),
+			Username: "kitchen1",
+			Password: "$2a$10$hash",
+			Role:     domain.RoleKitchen,
+		},
+	}, nil)
+	ctrl := NewControllerImpl(svc)
+
+	app := fiber.New()
+	app.Get("/api/v1/admins", ctrl.GetAdmins)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admins", nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "kitchen1")
+	assert.NotContains(t, string(body), "$2a$10$hash")
+	assert.NotContains(t, string(body), "password")
+}
+
+func TestGetProducts(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(svc *mocks.Service)
+		expectedStatus int
+	}{
+		{
+			name:  "Filters and sort are passed through",
+			query: "?name=nasi&min_price=1000&max_price=5000&in_stock=true&sort=-price&page=3&page_size=10",
+			setupMock: func(svc *mocks.Service) {
+				svc.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *domain.ProductFilter) bool {
+					return filter.Name == "nasi" &&
+						*filter.MinPrice == 1000 &&
+						*filter.MaxPrice == 5000 &&
+						filter.InStock &&
+						!filter.Deleted &&
+						filter.SortBy == "price" &&
+						filter.Desc &&
+						filter.Limit == 10 &&
+						filter.Offset == 20
+				})).Return([]*domain.Domain{{Id: "PRD001", Name: "Nasi Box"}}, &web.Meta{Total: 21, Page: 3, PageSize: 10}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "Unknown sort column",
+			query:          "?sort=password",
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Inverted price range",
+			query:          "?min_price=5000&max_price=1000",
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "Malformed cursor",
+			query:          "?cursor=not-a-cursor",
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "Cursor price is not a number",
+			query:          "?sort=price&cursor=" + helper.EncodeCursor(domain.Cursor{Value: "cheap", Id: "PRD001"}),
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "Cursor created_at is not a timestamp",
+			query:          "?sort=-created_at&cursor=" + helper.EncodeCursor(domain.Cursor{Value: "yesterday", Id: "PRD001"}),
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			tt.setupMock(svc)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Get("/api/v1/products", ctrl.GetProducts)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products"+tt.query, nil)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusOK {
+				var body web.Response[[]*domain.Domain]
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Len(t, body.Data, 1)
+				assert.Equal(t, &web.Meta{Total: 21, Page: 3, PageSize: 10}, body.Meta)
+			}
+		})
+	}
+}
+
+func TestGetProductTrash(t *testing.T) {
+	svc := mocks.NewService(t)
+	svc.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter *domain.ProductFilter) bool {
+		return filter.Deleted && filter.Name == "nasi"
+	})).Return([]*domain.Domain{}, &web.Meta{Total: 0, Page: 1, PageSize: 20}, nil)
+	ctrl := NewControllerImpl(svc)
+
+	app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+	app.Get("/api/v1/products/trash", ctrl.GetProductTrash)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/trash?name=nasi", nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestRestoreProduct(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(svc *mocks.Service)
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name: "Restored",
+			setupMock: func(svc *mocks.Service) {
+				svc.On("RestoreProduct", mock.Anything, "PRD001").Return(&domain.Domain{Id: "PRD001", Version: 5}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedETag:   `"5"`,
+		},
+		{
+			name: "Not in the trash",
+			setupMock: func(svc *mocks.Service) {
+				svc.On("RestoreProduct", mock.Anything, "PRD001").Return(nil, domain.NewError(domain.KindNotFound, "product", nil))
+			},
+			expectedStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			tt.setupMock(svc)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Post("/api/v1/products/:id/restore", ctrl.RestoreProduct)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/products/PRD001/restore", nil)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedETag, resp.Header.Get("ETag"))
+		})
+	}
+}
+
+func TestGetProduct(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(svc *mocks.Service)
+		expectedStatus int
+	}{
+		{
+			name: "Found",
+			setupMock: func(svc *mocks.Service) {
+				svc.On("GetProduct", mock.Anything, "PRD001").Return(&web.ProductDetailResponse{
+					Domain:       &domain.Domain{Id: "PRD001", Name: "Product 1"},
+					RecentOrders: []*domain.Orders{},
+				}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name: "Not found",
+			setupMock: func(svc *mocks.Service) {
+				svc.On("GetProduct", mock.Anything, "PRD001").Return(nil, domain.NewError(domain.KindNotFound, "product", nil))
+			},
+			expectedStatus: fiber.StatusNotFound,
+		},
+		{
+			name: "Unexpected failure",
+			setupMock: func(svc *mocks.Service) {
+				svc.On("GetProduct", mock.Anything, "PRD001").Return(nil, errors.New("connection refused"))
+			},
+			expectedStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			tt.setupMock(svc)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Get("/api/v1/products/:id", ctrl.GetProduct)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products/PRD001", nil)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestDeleteProductInUse(t *testing.T) {
+	svc := mocks.NewService(t)
+	svc.On("DeleteProduct", mock.Anything, "PRD001", 1).Return(domain.NewError(domain.KindForeignKeyInUse, "product", nil))
+	ctrl := NewControllerImpl(svc)
+
+	app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+	app.Delete("/api/v1/products/:id", ctrl.DeleteProduct)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/products/PRD001", nil)
+	req.Header.Set("If-Match", `"1"`)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+
+	var body web.Response[any]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "product_in_use", body.Error)
+	assert.Equal(t, "Product is still used by other records.", body.Status)
+}
+
+func TestDeleteProductImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "Deleted", expectedStatus: fiber.StatusNoContent},
+		{name: "Not found", err: domain.NewError(domain.KindNotFound, "image", nil), expectedStatus: fiber.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			svc.On("DeleteProductImage", mock.Anything, "PRD001", "img-1").Return(tt.err)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Delete("/api/v1/products/:id/images/:imageId", ctrl.DeleteProductImage)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/products/PRD001/images/img-1", nil)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(svc *mocks.Service)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "Deleted",
+			path: "/api/v1/categories/2",
+			setupMock: func(svc *mocks.Service) {
+				svc.On("DeleteCategory", mock.Anything, 2).Return(nil)
+			},
+			expectedStatus: fiber.StatusNoContent,
+		},
+		{
+			name: "Still has products",
+			path: "/api/v1/categories/2",
+			setupMock: func(svc *mocks.Service) {
+				svc.On("DeleteCategory", mock.Anything, 2).Return(domain.NewError(domain.KindForeignKeyInUse, "category", nil))
+			},
+			expectedStatus: fiber.StatusConflict,
+			expectedError:  "category_in_use",
+		},
+		{
+			name:           "Invalid id",
+			path:           "/api/v1/categories/rice",
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			tt.setupMock(svc)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Delete("/api/v1/categories/:id", ctrl.DeleteCategory)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, tt.path, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedError != "" {
+				var body web.Response[any]
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.expectedError, body.Error)
+			}
+		})
+	}
+}
+
+func TestAddPackage(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(svc *mocks.Service)
+		expectedStatus int
+	}{
+		{
+			name: "Added",
+			body: `{"name":"Paket Hemat","pricing":"discount","discount":50000,"items":[{"product_id":"PRD001","quantity":100},{"product_id":"PRD002","quantity":100}]}`,
+			setupMock: func(svc *mocks.Service) {
+				svc.On("AddPackage", mock.Anything, mock.MatchedBy(func(r *web.PackageRequest) bool {
+					return len(r.Items) == 2 && r.Discount == 50000
+				})).Return(&domain.Package{Id: "PKG001"}, nil)
+			},
+			expectedStatus: fiber.StatusCreated,
+		},
+		{
+			name:           "Fixed pricing without a price",
+			body:           `{"name":"Paket Hemat","pricing":"fixed","items":[{"product_id":"PRD001","quantity":100}]}`,
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Same product twice",
+			body:           `{"name":"Paket Hemat","pricing":"fixed","price":1000000,"items":[{"product_id":"PRD001","quantity":50},{"product_id":"PRD001","quantity":50}]}`,
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name: "Unknown product",
+			body: `{"name":"Paket Hemat","pricing":"fixed","price":1000000,"items":[{"product_id":"PRD404","quantity":50}]}`,
+			setupMock: func(svc *mocks.Service) {
+				svc.On("AddPackage", mock.Anything, mock.Anything).Return(nil, &domain.Error{Kind: domain.KindValidation, Entity: "product", Message: "Product PRD404 does not exist."})
+			},
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			tt.setupMock(svc)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Post("/api/v1/packages", ctrl.AddPackage)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/packages", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetOrders(t *testing.T) {
+	createdAt := "2026-10-01T08:30:00Z"
+	tests := []struct {
+		name           string
+		cursor         domain.Cursor
+		setupMock      func(svc *mocks.Service)
+		expectedStatus int
+	}{
+		{
+			name:   "Cursor is passed through",
+			cursor: domain.Cursor{Value: createdAt, Id: "order-1"},
+			setupMock: func(svc *mocks.Service) {
+				svc.On("GetOrders", mock.Anything, mock.MatchedBy(func(filter *domain.OrderFilter) bool {
+					return filter.After.Value == createdAt && filter.After.Id == "order-1"
+				})).Return([]*domain.Orders{}, &web.Meta{}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "Cursor value is not a timestamp",
+			cursor:         domain.Cursor{Value: "yesterday", Id: "order-1"},
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			tt.setupMock(svc)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Get("/api/v1/orders", ctrl.GetOrders)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?cursor="+helper.EncodeCursor(tt.cursor), nil)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetStockMovementsInvalidCursor(t *testing.T) {
+	ctrl := NewControllerImpl(mocks.NewService(t))
+
+	app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+	app.Get("/api/v1/products/:id/stock-movements", ctrl.GetStockMovements)
+
+	cursor := helper.EncodeCursor(domain.Cursor{Id: "latest"})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/PRD001/stock-movements?cursor="+cursor, nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestUpdateOrder(t *testing.T) {
+	tests := []struct {
+		name           string
+		ifMatch        string
+		body           string
+		setupMock      func(svc *mocks.Service)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:    "Updated",
+			ifMatch: `"3"`,
+			setupMock: func(svc *mocks.Service) {
+				svc.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order *domain.Orders) bool {
+					return order.Status == "preparing"
+				}), "order-1", 3).Return(nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:    "Illegal transition",
+			ifMatch: `"3"`,
+			setupMock: func(svc *mocks.Service) {
+				svc.On("UpdateOrder", mock.Anything, mock.Anything, "order-1", 3).Return(service.ErrInvalidOrderTransition)
+			},
+			expectedStatus: fiber.StatusConflict,
+			expectedError:  "order_status_conflict",
+		},
+		{
+			name:           "Note too long",
+			ifMatch:        `"3"`,
+			body:           `{"status":"preparing","note":"` + strings.Repeat("a", 256) + `"}`,
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusUnprocessableEntity,
+			expectedError:  "validation_failed",
+		},
+		{
+			name:           "Missing If-Match",
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusPreconditionRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			tt.setupMock(svc)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Put("/api/v1/orders/:id", ctrl.UpdateOrder)
+
+			body := tt.body
+			if body == "" {
+				body = `{"status":"preparing"}`
+			}
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/orders/order-1", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedError != "" {
+				var body web.Response[any]
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.expectedError, body.Error)
+			}
+		})
+	}
+}
+
+func TestAddAdminValidationErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		acceptLanguage  string
+		expectedMessage string
+	}{
+		{
+			name:            "English",
+			acceptLanguage:  "en-US,en;q=0.9",
+			expectedMessage: "password must be at least 8 characters in length",
+		},
+		{
+			name:            "Indonesian",
+			acceptLanguage:  "id-ID,id;q=0.9,en;q=0.8",
+			expectedMessage: "panjang minimal password adalah 8 karakter",
+		},
+		{
+			name:            "Unsupported language falls back to English",
+			acceptLanguage:  "fr-FR",
+			expectedMessage: "password must be at least 8 characters in length",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := NewControllerImpl(mocks.NewService(t))
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Post("/api/v1/admins", ctrl.AddAdmin)
+
+			reqBody, _ := json.Marshal(map[string]string{"username": "kitchen1", "password": "short", "role": "chef"})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admins", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+			var body web.Response[[]domain.FieldError]
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, "validation_failed", body.Error)
+			if assert.Len(t, body.Data, 2) {
+				assert.Equal(t, domain.FieldError{Field: "password", Rule: "min", Message: tt.expectedMessage}, body.Data[0])
+				assert.Equal(t, "role", body.Data[1].Field)
+				assert.Equal(t, "oneof", body.Data[1].Rule)
+			}
+		})
+	}
+}
+
 // func TestDeleteOrder(t *testing.T) {
 // 	id := "1"
 // 	tests := []struct {
@@ -111,3 +792,121 @@ func TestLogin(t *testing.T) {
 // 		})
 // 	}
 // }
+
+func TestUpdateProduct(t *testing.T) {
+	onlyStock := func(r *web.UpdateProductRequest) bool {
+		return r.Stock != nil && *r.Stock == 7 && r.Name == nil && r.Description == nil && r.Price == nil
+	}
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		body           func() (io.Reader, string)
+		setupMock      func(svc *mocks.Service)
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:    "JSON patch",
+			ifMatch: `"3"`,
+			body:    jsonBody(`{"stock": 7}`),
+			setupMock: func(svc *mocks.Service) {
+				svc.On("UpdateProduct", mock.Anything, mock.MatchedBy(onlyStock), mock.Anything, "PRD001", 3).Return(&domain.Domain{Id: "PRD001", Stock: 7, Version: 4}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedETag:   `"4"`,
+		},
+		{
+			name:    "Multipart patch",
+			ifMatch: `"3"`,
+			body: func() (io.Reader, string) {
+				var buf bytes.Buffer
+				writer := multipart.NewWriter(&buf)
+				writer.WriteField("stock", "7")
+				writer.Close()
+				return &buf, writer.FormDataContentType()
+			},
+			setupMock: func(svc *mocks.Service) {
+				svc.On("UpdateProduct", mock.Anything, mock.MatchedBy(onlyStock), mock.Anything, "PRD001", 3).Return(&domain.Domain{Id: "PRD001", Stock: 7, Version: 4}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedETag:   `"4"`,
+		},
+		{
+			name:           "Empty patch",
+			ifMatch:        `"3"`,
+			body:           jsonBody(`{}`),
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "Invalid field",
+			ifMatch:        `"3"`,
+			body:           jsonBody(`{"name": "abc"}`),
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:    "Not found",
+			ifMatch: `"3"`,
+			body:    jsonBody(`{"stock": 7}`),
+			setupMock: func(svc *mocks.Service) {
+				svc.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything, "PRD001", 3).Return(nil, domain.NewError(domain.KindNotFound, "product", nil))
+			},
+			expectedStatus: fiber.StatusNotFound,
+		},
+		{
+			name:    "Stale version",
+			ifMatch: `"3"`,
+			body:    jsonBody(`{"stock": 7}`),
+			setupMock: func(svc *mocks.Service) {
+				svc.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything, "PRD001", 3).Return(nil, domain.NewError(domain.KindStale, "product", nil))
+			},
+			expectedStatus: fiber.StatusPreconditionFailed,
+		},
+		{
+			name:           "Missing If-Match",
+			body:           jsonBody(`{"stock": 7}`),
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusPreconditionRequired,
+		},
+		{
+			name:           "Weak If-Match",
+			ifMatch:        `W/"3"`,
+			body:           jsonBody(`{"stock": 7}`),
+			setupMock:      func(svc *mocks.Service) {},
+			expectedStatus: fiber.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			tt.setupMock(svc)
+			ctrl := NewControllerImpl(svc)
+
+			app := fiber.New(fiber.Config{ErrorHandler: web.ErrorHandler})
+			app.Patch("/api/v1/products/:id", ctrl.UpdateProduct)
+
+			body, contentType := tt.body()
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/products/PRD001", body)
+			req.Header.Set("Content-Type", contentType)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedETag, resp.Header.Get("ETag"))
+		})
+	}
+}
+
+func jsonBody(raw string) func() (io.Reader, string) {
+	return func() (io.Reader, string) {
+		return bytes.NewBufferString(raw), fiber.MIMEApplicationJSON
+	}
+}
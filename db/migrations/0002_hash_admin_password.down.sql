@@ -0,0 +1,2 @@
+UPDATE admin SET password = 'hashed_admin_password'
+WHERE id = 'e7b8a9d4-3f5a-4c82-b7e2-2c3f49b0e9c1';
@@ -0,0 +1,3 @@
+-- Replace the plaintext seed password with a bcrypt hash of 'admin123'.
+UPDATE admin SET password = '$2a$10$UoDQ..OVsX1GZ/ydVIIbKuYetr9nrrNmBwTxdOv6av67BTxAJvf1m'
+WHERE id = 'e7b8a9d4-3f5a-4c82-b7e2-2c3f49b0e9c1' AND password = 'hashed_admin_password';
@@ -0,0 +1,19 @@
+CREATE TABLE refresh_tokens (
+    id CHAR(36) PRIMARY KEY,
+    family_id CHAR(36) NOT NULL,
+    admin_id CHAR(36) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (admin_id) REFERENCES admin(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+
+CREATE TABLE revoked_access_tokens (
+    jti CHAR(36) PRIMARY KEY,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_revoked_access_tokens_expires ON revoked_access_tokens(expires_at);
@@ -0,0 +1,3 @@
+ALTER TABLE admin
+    DROP CHECK chk_admin_role,
+    DROP COLUMN role;
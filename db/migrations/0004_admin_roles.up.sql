@@ -0,0 +1,3 @@
+ALTER TABLE admin
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'owner',
+    ADD CONSTRAINT chk_admin_role CHECK (role IN ('owner', 'manager', 'kitchen', 'cashier'));
@@ -0,0 +1,4 @@
+ALTER TABLE admin
+    DROP COLUMN modified_at,
+    DROP COLUMN created_at,
+    DROP COLUMN disabled_at;
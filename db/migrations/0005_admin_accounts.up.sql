@@ -0,0 +1,4 @@
+ALTER TABLE admin
+    ADD COLUMN disabled_at DATETIME NULL,
+    ADD COLUMN created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS admin_recovery_codes;
+
+ALTER TABLE admin
+    DROP COLUMN totp_last_step,
+    DROP COLUMN totp_enabled,
+    DROP COLUMN totp_secret;
@@ -0,0 +1,15 @@
+ALTER TABLE admin
+    ADD COLUMN totp_secret VARCHAR(64) NULL,
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE admin_recovery_codes (
+    id CHAR(36) PRIMARY KEY,
+    admin_id CHAR(36) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (admin_id) REFERENCES admin(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_admin_recovery_codes_admin ON admin_recovery_codes(admin_id);
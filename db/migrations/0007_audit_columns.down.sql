@@ -0,0 +1,6 @@
+ALTER TABLE orders
+    DROP COLUMN modified_by;
+
+ALTER TABLE products
+    DROP COLUMN modified_by,
+    DROP COLUMN created_by;
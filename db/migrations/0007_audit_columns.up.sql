@@ -0,0 +1,6 @@
+ALTER TABLE products
+    ADD COLUMN created_by CHAR(36) NULL,
+    ADD COLUMN modified_by CHAR(36) NULL;
+
+ALTER TABLE orders
+    ADD COLUMN modified_by CHAR(36) NULL;
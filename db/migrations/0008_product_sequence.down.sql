@@ -0,0 +1,8 @@
+SET FOREIGN_KEY_CHECKS = 0;
+
+ALTER TABLE orders MODIFY product_id VARCHAR(6) NOT NULL;
+ALTER TABLE products MODIFY id VARCHAR(6) NOT NULL;
+
+SET FOREIGN_KEY_CHECKS = 1;
+
+DROP TABLE sequences;
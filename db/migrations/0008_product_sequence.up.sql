@@ -0,0 +1,16 @@
+CREATE TABLE sequences (
+    name VARCHAR(50) PRIMARY KEY,
+    value BIGINT NOT NULL
+);
+
+INSERT INTO sequences (name, value)
+SELECT 'products', COALESCE(MAX(CAST(SUBSTRING(id, 4) AS UNSIGNED)), 0)
+FROM products
+WHERE id REGEXP '^PRD[0-9]+$';
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+ALTER TABLE products MODIFY id VARCHAR(12) NOT NULL;
+ALTER TABLE orders MODIFY product_id VARCHAR(12) NOT NULL;
+
+SET FOREIGN_KEY_CHECKS = 1;
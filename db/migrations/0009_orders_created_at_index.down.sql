@@ -0,0 +1 @@
+DROP INDEX idx_orders_created_at ON orders;
@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    DROP COLUMN version;
+
+ALTER TABLE products
+    DROP COLUMN version;
@@ -0,0 +1,5 @@
+ALTER TABLE products
+    ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1;
+
+ALTER TABLE orders
+    ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1;
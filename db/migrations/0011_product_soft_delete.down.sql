@@ -0,0 +1,4 @@
+ALTER TABLE products
+    DROP INDEX idx_products_deleted_at,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+ALTER TABLE products
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by CHAR(36) NULL,
+    ADD INDEX idx_products_deleted_at (deleted_at);
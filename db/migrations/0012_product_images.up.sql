@@ -0,0 +1,14 @@
+CREATE TABLE product_images (
+    id CHAR(36) PRIMARY KEY,
+    product_id VARCHAR(12) NOT NULL,
+    object_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size INT NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by CHAR(36) NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_product_images_product ON product_images(product_id, position);
@@ -0,0 +1,22 @@
+CREATE TABLE categories (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    created_by CHAR(36) NULL,
+    modified_by CHAR(36) NULL
+);
+
+CREATE INDEX idx_categories_position ON categories(position, name);
+
+ALTER TABLE products
+    ADD COLUMN category_id INT NULL,
+    ADD CONSTRAINT fk_products_category FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL;
+
+INSERT INTO categories (name, position) VALUES
+('Rice Boxes', 0),
+('Snacks', 1),
+('Beverages', 2),
+('Packages', 3);
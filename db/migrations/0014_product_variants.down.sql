@@ -0,0 +1,10 @@
+DROP TABLE order_options;
+
+ALTER TABLE orders
+    DROP FOREIGN KEY fk_orders_variant,
+    DROP COLUMN variant_name,
+    DROP COLUMN variant_id;
+
+DROP TABLE product_options;
+DROP TABLE product_option_groups;
+DROP TABLE product_variants;
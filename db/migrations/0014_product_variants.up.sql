@@ -0,0 +1,58 @@
+CREATE TABLE product_variants (
+    id CHAR(36) PRIMARY KEY,
+    product_id VARCHAR(12) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    price_delta INT NOT NULL DEFAULT 0,
+    stock INT NULL,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_product_variants_name (product_id, name),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE TABLE product_option_groups (
+    id CHAR(36) PRIMARY KEY,
+    product_id VARCHAR(12) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    min_select INT NOT NULL DEFAULT 0,
+    max_select INT NOT NULL DEFAULT 0,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_product_option_groups_name (product_id, name),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE TABLE product_options (
+    id CHAR(36) PRIMARY KEY,
+    group_id CHAR(36) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    price_delta INT NOT NULL DEFAULT 0,
+    stock INT NULL,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_product_options_name (group_id, name),
+    FOREIGN KEY (group_id) REFERENCES product_option_groups(id) ON DELETE CASCADE
+);
+
+-- Orders keep the names and price deltas they were placed with, so editing or
+-- removing a variant or option later does not rewrite order history.
+ALTER TABLE orders
+    ADD COLUMN variant_id CHAR(36) NULL,
+    ADD COLUMN variant_name VARCHAR(50) NULL,
+    ADD CONSTRAINT fk_orders_variant FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE SET NULL;
+
+CREATE TABLE order_options (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    option_id CHAR(36) NULL,
+    group_name VARCHAR(50) NOT NULL,
+    option_name VARCHAR(50) NOT NULL,
+    price_delta INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (option_id) REFERENCES product_options(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_order_options_order ON order_options(order_id);
@@ -0,0 +1,12 @@
+-- Package orders have no product to fall back to and cannot be kept.
+DELETE FROM orders WHERE product_id IS NULL;
+
+ALTER TABLE orders
+    DROP FOREIGN KEY fk_orders_package,
+    DROP COLUMN package_id,
+    MODIFY product_id VARCHAR(12) NOT NULL;
+
+DELETE FROM sequences WHERE name = 'packages';
+
+DROP TABLE package_items;
+DROP TABLE packages;
@@ -0,0 +1,34 @@
+-- A package bundles existing products, e.g. 100 rice boxes with a snack and
+-- a drink each. Its price is either the fixed price or the sum of its items
+-- minus discount.
+CREATE TABLE packages (
+    id VARCHAR(12) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    pricing VARCHAR(10) NOT NULL DEFAULT 'fixed',
+    price INT NOT NULL DEFAULT 0,
+    discount INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    created_by CHAR(36) NULL,
+    modified_by CHAR(36) NULL,
+    CONSTRAINT chk_packages_pricing CHECK (pricing IN ('fixed', 'discount'))
+);
+
+CREATE TABLE package_items (
+    package_id VARCHAR(12) NOT NULL,
+    product_id VARCHAR(12) NOT NULL,
+    quantity INT NOT NULL,
+    PRIMARY KEY (package_id, product_id),
+    CONSTRAINT chk_package_items_quantity CHECK (quantity > 0),
+    FOREIGN KEY (package_id) REFERENCES packages(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+INSERT INTO sequences (name, value) VALUES ('packages', 0);
+
+-- An order is for either a product or a package.
+ALTER TABLE orders
+    MODIFY product_id VARCHAR(12) NULL,
+    ADD COLUMN package_id VARCHAR(12) NULL,
+    ADD CONSTRAINT fk_orders_package FOREIGN KEY (package_id) REFERENCES packages(id);
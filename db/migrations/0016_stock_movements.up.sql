@@ -0,0 +1,23 @@
+-- Every change to products.stock is recorded here; stock_after is the stock
+-- once the movement was applied.
+CREATE TABLE stock_movements (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id VARCHAR(12) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    delta INT NOT NULL,
+    stock_after INT NOT NULL,
+    reason VARCHAR(255) NULL,
+    order_id CHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by CHAR(36) NULL,
+    CONSTRAINT chk_stock_movements_kind CHECK (kind IN ('initial', 'adjustment', 'reservation', 'restock', 'waste')),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_stock_movements_product ON stock_movements(product_id, id);
+
+-- Open the ledger with the stock products have today.
+INSERT INTO stock_movements (product_id, kind, delta, stock_after, reason)
+SELECT id, 'initial', stock, stock, 'Opening balance'
+FROM products;
@@ -0,0 +1,3 @@
+-- Set while an order holds stock taken from its products, so a cancelled
+-- order only gives back what it took.
+ALTER TABLE orders ADD COLUMN stock_reserved BOOLEAN NOT NULL DEFAULT FALSE;
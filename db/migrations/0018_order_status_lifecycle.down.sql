@@ -0,0 +1,3 @@
+ALTER TABLE orders
+    DROP CHECK chk_orders_status,
+    MODIFY status VARCHAR(20) DEFAULT 'pending';
@@ -0,0 +1,11 @@
+-- Map the statuses orders were given before the lifecycle existed onto it.
+UPDATE orders SET status = 'preparing' WHERE status = 'processing';
+UPDATE orders SET status = 'completed' WHERE status IN ('done', 'delivered', 'finished');
+UPDATE orders SET status = 'cancelled' WHERE status = 'canceled';
+UPDATE orders SET status = 'pending'
+WHERE status IS NULL
+   OR status NOT IN ('pending', 'confirmed', 'preparing', 'ready', 'delivering', 'completed', 'cancelled', 'refunded');
+
+ALTER TABLE orders
+    MODIFY status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD CONSTRAINT chk_orders_status CHECK (status IN ('pending', 'confirmed', 'preparing', 'ready', 'delivering', 'completed', 'cancelled', 'refunded'));
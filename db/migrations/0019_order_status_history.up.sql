@@ -0,0 +1,15 @@
+-- One row per status change of an order. username is copied from the admin's
+-- token so the timeline still reads the same after the admin is renamed or
+-- removed.
+CREATE TABLE order_status_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    username VARCHAR(100) NULL,
+    note VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_order_status_history_order ON order_status_history(order_id, id);
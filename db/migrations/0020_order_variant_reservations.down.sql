@@ -0,0 +1,2 @@
+ALTER TABLE order_options DROP COLUMN stock_reserved;
+ALTER TABLE orders DROP COLUMN variant_reserved;
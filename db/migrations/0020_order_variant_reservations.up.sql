@@ -0,0 +1,5 @@
+-- How much of its variant's and options' own stock an order holds, so that
+-- releasing it gives back exactly that. Variants and options without their
+-- own stock draw on the product and are reserved through stock_movements.
+ALTER TABLE orders ADD COLUMN variant_reserved INT NOT NULL DEFAULT 0;
+ALTER TABLE order_options ADD COLUMN stock_reserved INT NOT NULL DEFAULT 0;
@@ -1,9 +1,27 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleOwner   = "owner"
+	RoleManager = "manager"
+	RoleKitchen = "kitchen"
+	RoleCashier = "cashier"
+)
 
 type Admin struct {
-	Id       uuid.UUID `json:"id"`
-	Username string    `json:"username" validate:"required"`
-	Password string    `json:"password" validate:"required"`
+	Id               uuid.UUID  `json:"id"`
+	Username         string     `json:"username" validate:"required"`
+	Password         string     `json:"password" validate:"required"`
+	Role             string     `json:"role"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	TOTPSecret       string     `json:"-"`
+	TOTPLastStep     int64      `json:"-"`
+	DisabledAt       *time.Time `json:"disabled_at"`
+	CreatedAt        *time.Time `json:"created_at"`
+	ModifiedAt       *time.Time `json:"modified_at"`
 }
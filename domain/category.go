@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Category is a menu section such as rice boxes or beverages. Categories are
+// listed by Position, then by name.
+type Category struct {
+	Id           int        `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Position     int        `json:"position"`
+	ProductCount int        `json:"product_count"`
+	CreatedAt    *time.Time `json:"created_at"`
+	ModifiedAt   *time.Time `json:"modified_at"`
+}
@@ -5,23 +5,76 @@ import (
 )
 
 type Domain struct {
-	Id          string     `json:"id" validate:"required"`
-	Name        string     `json:"name" validate:"required,min=5,max=50"`
-	Description string     `json:"description" validate:"alphanum"`
-	Stock       int        `json:"stock" validate:"required,number"`
-	Price       int        `json:"price" validate:"required,number"`
-	CreatedAt   *time.Time `json:"created_at" validate:"required"`
-	ModifiedAt  *time.Time `json:"modified_at" validate:"required"`
+	Id          string          `json:"id" validate:"required"`
+	Name        string          `json:"name" validate:"required,min=5,max=50"`
+	Description string          `json:"description" validate:"alphanum"`
+	Stock       int             `json:"stock" validate:"required,number"`
+	Price       int             `json:"price" validate:"required,number"`
+	CategoryId  *int            `json:"category_id"`
+	CreatedAt   *time.Time      `json:"created_at" validate:"required"`
+	ModifiedAt  *time.Time      `json:"modified_at" validate:"required"`
+	Version     int             `json:"version"`
+	DeletedAt   *time.Time      `json:"deleted_at,omitempty"`
+	Images      []*ProductImage `json:"images"`
 }
 
+// ProductImage is an uploaded photo of a product. The storage keys stay
+// internal; clients get the URLs the service fills in.
+type ProductImage struct {
+	Id           string     `json:"id"`
+	ProductId    string     `json:"product_id"`
+	Key          string     `json:"-"`
+	ThumbnailKey string     `json:"-"`
+	URL          string     `json:"url"`
+	ThumbnailURL string     `json:"thumbnail_url"`
+	ContentType  string     `json:"content_type"`
+	Size         int        `json:"size"`
+	Position     int        `json:"position"`
+	CreatedAt    *time.Time `json:"created_at"`
+}
+
+// ProductPatch lists the product columns to change; nil fields are left
+// untouched. A CategoryId of 0 removes the product from its category. Stock
+// is not part of it: stock only changes through stock movements.
+type ProductPatch struct {
+	Name        *string
+	Description *string
+	Price       *int
+	CategoryId  *int
+	ModifiedAt  *time.Time
+}
+
+// Orders is an order for either a product or a package. A package order has
+// an empty ProductId and ProductName holds the package name. Note is only read
+// on status updates and is kept with the change in the order's history.
 type Orders struct {
-	Id          string     `json:"id"`
-	ProductId   string     `json:"product_id"`
-	ProductName string     `json:"product_name"`
-	Username    string     `json:"username"`
-	Quantity    int        `json:"quantity"`
-	Total       float64    `json:"total" validate:"required"`
-	Status      string     `json:"status"`
-	CreatedAt   *time.Time `json:"created_at" validate:"required"`
-	ModifiedAt  *time.Time `json:"modified_at" validate:"required"`
+	Id                  string         `json:"id"`
+	ProductId           string         `json:"product_id"`
+	PackageId           *string        `json:"package_id"`
+	ProductName         string         `json:"product_name"`
+	Username            string         `json:"username"`
+	Quantity            int            `json:"quantity"`
+	Total               float64        `json:"total" validate:"required"`
+	VariantId           *string        `json:"variant_id"`
+	VariantName         *string        `json:"variant_name"`
+	Options             []*OrderOption `json:"options"`
+	Status              string         `json:"status"`
+	AllowedNextStatuses []string       `json:"allowed_next_statuses"`
+	StockReserved       bool           `json:"stock_reserved"`
+	Note                string         `json:"note,omitempty" validate:"max=255"`
+	CreatedAt           *time.Time     `json:"created_at" validate:"required"`
+	ModifiedAt          *time.Time     `json:"modified_at" validate:"required"`
+	Version             int            `json:"version"`
+}
+
+// OrderStatusChange is one step in an order's timeline. Username is the admin
+// who made the change, as it read in their token at the time.
+type OrderStatusChange struct {
+	Id         int64      `json:"id"`
+	OrderId    string     `json:"-"`
+	FromStatus string     `json:"from_status"`
+	ToStatus   string     `json:"to_status"`
+	Username   *string    `json:"username"`
+	Note       string     `json:"note"`
+	CreatedAt  *time.Time `json:"created_at"`
 }
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+type ErrorKind string
+
+const (
+	KindNotFound        ErrorKind = "not_found"
+	KindConflict        ErrorKind = "conflict"
+	KindValidation      ErrorKind = "validation_failed"
+	KindForeignKeyInUse ErrorKind = "in_use"
+	// KindStale means the row changed since the client last read it.
+	KindStale ErrorKind = "precondition_failed"
+)
+
+// Error is a failure the client can act on. Entity names the resource, e.g.
+// "product", and together with Kind forms the machine-readable error code.
+type Error struct {
+	Kind    ErrorKind
+	Entity  string
+	Message string
+	Err     error
+}
+
+// FieldError is one failed validation rule on a request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any error of the same kind and entity, so a sentinel such as
+// service.ErrProductNotFound matches whatever the repository returned.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Entity == e.Entity
+}
+
+func (e *Error) Code() string {
+	return e.Entity + "_" + string(e.Kind)
+}
+
+func NewError(kind ErrorKind, entity string, err error) *Error {
+	name := strings.ToUpper(entity[:1]) + strings.ReplaceAll(entity[1:], "_", " ")
+
+	var message string
+	switch kind {
+	case KindNotFound:
+		message = name + " not found."
+	case KindConflict:
+		message = name + " already exists."
+	case KindForeignKeyInUse:
+		message = name + " is still used by other records."
+	case KindStale:
+		message = name + " was changed by someone else. Reload it and try again."
+	default:
+		message = name + " data is invalid."
+	}
+
+	return &Error{Kind: kind, Entity: entity, Message: message, Err: err}
+}
+
+func IsKind(err error, kind ErrorKind) bool {
+	var domainErr *Error
+	return errors.As(err, &domainErr) && domainErr.Kind == kind
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Cursor marks the last row of a page for keyset pagination: the value of
+// the sort column, as text, and the id that breaks ties between equal values.
+type Cursor struct {
+	Value string `json:"v"`
+	Id    string `json:"id"`
+}
+
+// ProductFilter lists live products, or only the ones in the trash when
+// Deleted is set.
+type ProductFilter struct {
+	Name       string
+	MinPrice   *int
+	MaxPrice   *int
+	InStock    bool
+	CategoryId *int
+	Deleted    bool
+	SortBy     string
+	Desc       bool
+	Limit      int
+	Offset     int
+	After      *Cursor
+}
+
+// OrderFilter pages through orders newest first. CreatedBefore is exclusive.
+type OrderFilter struct {
+	Status        string
+	Username      string
+	ProductId     string
+	CreatedFrom   *time.Time
+	CreatedBefore *time.Time
+	MinTotal      *float64
+	MaxTotal      *float64
+	Limit         int
+	After         *Cursor
+}
+
+// StockMovementFilter pages through a product's stock movements newest first.
+type StockMovementFilter struct {
+	ProductId string
+	Limit     int
+	After     *Cursor
+}
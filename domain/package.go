@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+const (
+	PackagePricingFixed    = "fixed"
+	PackagePricingDiscount = "discount"
+)
+
+// Package is a bundle of existing products sold as one item. With fixed
+// pricing Price is what the admin set; with discount pricing it is the sum of
+// the items minus Discount. Available is how many packages the current stock
+// of the items covers.
+type Package struct {
+	Id          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Pricing     string         `json:"pricing"`
+	Price       int            `json:"price"`
+	Discount    int            `json:"discount"`
+	Available   int            `json:"available"`
+	Items       []*PackageItem `json:"items"`
+	CreatedAt   *time.Time     `json:"created_at"`
+	ModifiedAt  *time.Time     `json:"modified_at"`
+}
+
+// PackageItem is a product and how many of it go into one package. The
+// product's name, price and stock are read along with it; Deleted is set
+// while the product is in the trash.
+type PackageItem struct {
+	PackageId   string `json:"-"`
+	ProductId   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   int    `json:"unit_price"`
+	Stock       int    `json:"stock"`
+	Deleted     bool   `json:"deleted"`
+}
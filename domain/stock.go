@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// Kinds of stock movement.
+const (
+	StockInitial     = "initial"
+	StockAdjustment  = "adjustment"
+	StockReservation = "reservation"
+	StockRestock     = "restock"
+	StockWaste       = "waste"
+)
+
+// StockMovement is one change to a product's stock. Delta is negative when
+// stock goes down and StockAfter is the stock once the change was applied.
+// OrderId is set for reservations and restocks; Actor is the username of the
+// admin who made the change, if any.
+type StockMovement struct {
+	Id         int64      `json:"id"`
+	ProductId  string     `json:"product_id"`
+	Kind       string     `json:"kind"`
+	Delta      int        `json:"delta"`
+	StockAfter int        `json:"stock_after"`
+	Reason     string     `json:"reason"`
+	OrderId    *string    `json:"order_id"`
+	Actor      *string    `json:"actor"`
+	CreatedAt  *time.Time `json:"created_at"`
+}
+
+// StockLine is a product an order takes stock from and how much of it.
+type StockLine struct {
+	ProductId   string
+	ProductName string
+	Quantity    int
+}
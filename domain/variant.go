@@ -0,0 +1,44 @@
+package domain
+
+// ProductVariant is one portion of a product, e.g. small or large. Its price
+// is the product price plus PriceDelta. A nil Stock means the variant draws on
+// the product's stock.
+type ProductVariant struct {
+	Id         string `json:"id"`
+	ProductId  string `json:"product_id"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+	Stock      *int   `json:"stock"`
+	Position   int    `json:"position"`
+}
+
+// OptionGroup is a set of add-ons such as spice level. A customer picks at
+// least MinSelect options and at most MaxSelect, where 0 means no limit.
+type OptionGroup struct {
+	Id        string           `json:"id"`
+	ProductId string           `json:"product_id"`
+	Name      string           `json:"name"`
+	MinSelect int              `json:"min_select"`
+	MaxSelect int              `json:"max_select"`
+	Position  int              `json:"position"`
+	Options   []*ProductOption `json:"options"`
+}
+
+type ProductOption struct {
+	Id         string `json:"id"`
+	GroupId    string `json:"group_id"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+	Stock      *int   `json:"stock"`
+	Position   int    `json:"position"`
+}
+
+// OrderOption is an option chosen on an order, copied at order time. OptionId
+// is nil once the option itself has been removed.
+type OrderOption struct {
+	OrderId    string  `json:"-"`
+	OptionId   *string `json:"option_id"`
+	GroupName  string  `json:"group_name"`
+	Name       string  `json:"name"`
+	PriceDelta int     `json:"price_delta"`
+}
@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type claimsContextKey struct{}
+
+// Claims is the payload of an access token. Subject holds the admin id and ID
+// the jti used for revocation.
+type Claims struct {
+	Type     string   `json:"typ"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+func (c *Claims) AdminId() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+func (c *Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// WithClaims attaches the authenticated admin to ctx so services and
+// repositories can tell who is making a change.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"catering-admin-go/domain"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor turns a cursor into the opaque string handed to clients.
+func EncodeCursor(cursor domain.Cursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(encoded string) (*domain.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor domain.Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// ProductCursorValue turns the cursor value of a product list sorted by
+// sortBy back into the type of that column.
+func ProductCursorValue(sortBy string, value string) (interface{}, error) {
+	switch sortBy {
+	case "price", "stock":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return n, nil
+	case "created_at":
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return t, nil
+	default:
+		return value, nil
+	}
+}
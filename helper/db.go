@@ -20,7 +20,9 @@ func NewDb() (*sql.DB, func(), error) {
 	port := os.Getenv("DB_PORT")
 	name := os.Getenv("DB_NAME")
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, pass, host, port, name)
+	// clientFoundRows makes RowsAffected count matched rows, so an UPDATE that
+	// changes nothing is not mistaken for a missing row.
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&clientFoundRows=true", user, pass, host, port, name)
 
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -0,0 +1,104 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	ThumbnailSize = 320
+	// maxImagePixels guards against small files that decode into huge images.
+	maxImagePixels = 40_000_000
+	// thumbnailSamples caps how many source pixels are read along each axis
+	// for one thumbnail pixel, so scaling costs the same for any upload size.
+	thumbnailSamples = 4
+)
+
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// imageExtensions lists the accepted content types, as sniffed from the file
+// itself, and the extension they are stored under.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// DetectImage sniffs the content type of data and returns it with the file
+// extension to store it under, rejecting anything that is not an accepted
+// image.
+func DetectImage(data []byte) (string, string, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return "", "", ErrUnsupportedImage
+	}
+	return contentType, ext, nil
+}
+
+// MakeThumbnail decodes data and returns a copy scaled down to fit within
+// ThumbnailSize, encoded in the same format as the original.
+func MakeThumbnail(data []byte, contentType string) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width*config.Height > maxImagePixels {
+		return nil, ErrUnsupportedImage
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	var buf bytes.Buffer
+	thumb := Thumbnail(img, ThumbnailSize)
+	if contentType == "image/png" {
+		err = png.Encode(&buf, thumb)
+	} else {
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Thumbnail scales img down so its longer side is at most size, averaging
+// an evenly spaced grid of the source pixels that fall into each target
+// pixel. Images that already fit are returned as they are.
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+
+	tw, th := size, max(h*size/w, 1)
+	if h > w {
+		tw, th = max(w*size/h, 1), size
+	}
+
+	dst := image.NewRGBA64(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := bounds.Min.Y+y*h/th, bounds.Min.Y+(y+1)*h/th
+		ystep := max((y1-y0)/thumbnailSamples, 1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := bounds.Min.X+x*w/tw, bounds.Min.X+(x+1)*w/tw
+			xstep := max((x1-x0)/thumbnailSamples, 1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy += ystep {
+				for sx := x0; sx < x1; sx += xstep {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,78 @@
+package helper
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectImage(t *testing.T) {
+	contentType, ext, err := DetectImage(encodePNG(t, 2, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, ".png", ext)
+
+	_, _, err = DetectImage([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	tests := []struct {
+		name           string
+		width, height  int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{name: "Landscape", width: 800, height: 400, expectedWidth: 320, expectedHeight: 160},
+		{name: "Portrait", width: 300, height: 960, expectedWidth: 100, expectedHeight: 320},
+		{name: "Already small", width: 50, height: 40, expectedWidth: 50, expectedHeight: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb, err := MakeThumbnail(encodePNG(t, tt.width, tt.height), "image/png")
+			assert.NoError(t, err)
+
+			config, format, err := image.DecodeConfig(bytes.NewReader(thumb))
+			assert.NoError(t, err)
+			assert.Equal(t, "png", format)
+			assert.Equal(t, tt.expectedWidth, config.Width)
+			assert.Equal(t, tt.expectedHeight, config.Height)
+		})
+	}
+
+	_, err := MakeThumbnail([]byte("\x89PNG\r\n\x1a\nbroken"), "image/png")
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+}
+
+func TestThumbnailLargeImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4000, 3000))
+	for i := 0; i < len(src.Pix); i += 4 {
+		src.Pix[i], src.Pix[i+1], src.Pix[i+2], src.Pix[i+3] = 200, 100, 50, 255
+	}
+
+	thumb := Thumbnail(src, ThumbnailSize)
+	assert.Equal(t, image.Rect(0, 0, 320, 240), thumb.Bounds())
+
+	r, g, b, a := thumb.At(160, 120).RGBA()
+	assert.Equal(t, []uint32{200, 100, 50, 255}, []uint32{r >> 8, g >> 8, b >> 8, a >> 8})
+}
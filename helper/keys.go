@@ -0,0 +1,255 @@
+package helper
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultKeyOverlap = 24 * time.Hour
+
+var (
+	keySet     *KeySet
+	keySetErr  error
+	keySetOnce sync.Once
+
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+type SigningKey struct {
+	Kid     string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	// NotAfter is when a retired key stops being accepted; zero means the key
+	// is the active one.
+	NotAfter time.Time
+}
+
+type KeySet struct {
+	active     *SigningKey
+	keys       map[string]*SigningKey
+	hmacSecret []byte
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// Keys loads the signing keys once from the environment:
+//   - JWT_KEYS_DIR holds one PEM private key (RSA or Ed25519) per file, named <kid>.pem
+//   - JWT_ACTIVE_KID picks the signing key, defaulting to the newest file
+//   - JWT_KEY_OVERLAP is how long the other keys keep verifying after the
+//     active key was added (default 24h)
+//   - JWT_SECRET, when set, keeps verifying legacy HS256 tokens and is used to
+//     sign when no key directory is configured
+func Keys() (*KeySet, error) {
+	keySetOnce.Do(func() {
+		overlap := defaultKeyOverlap
+		if raw := os.Getenv("JWT_KEY_OVERLAP"); raw != "" {
+			overlap, keySetErr = time.ParseDuration(raw)
+			if keySetErr != nil {
+				return
+			}
+		}
+		keySet, keySetErr = LoadKeySet(os.Getenv("JWT_KEYS_DIR"), os.Getenv("JWT_ACTIVE_KID"), overlap, os.Getenv("JWT_SECRET"))
+	})
+	return keySet, keySetErr
+}
+
+func LoadKeySet(dir string, activeKid string, overlap time.Duration, hmacSecret string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*SigningKey)}
+	if hmacSecret != "" {
+		ks.hmacSecret = []byte(hmacSecret)
+	}
+
+	if dir == "" {
+		if ks.hmacSecret == nil {
+			return nil, errors.New("either JWT_KEYS_DIR or JWT_SECRET must be set")
+		}
+		return ks, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no signing keys found in %s", dir)
+	}
+
+	modTimes := make(map[string]time.Time)
+	for _, path := range paths {
+		key, err := loadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[key.Kid] = key
+		modTimes[key.Kid] = info.ModTime()
+	}
+
+	if activeKid == "" {
+		kids := make([]string, 0, len(modTimes))
+		for kid := range modTimes {
+			kids = append(kids, kid)
+		}
+		sort.Slice(kids, func(i, j int) bool { return modTimes[kids[i]].After(modTimes[kids[j]]) })
+		activeKid = kids[0]
+	}
+
+	active, ok := ks.keys[activeKid]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found in %s", activeKid, dir)
+	}
+	ks.active = active
+
+	retireAt := modTimes[activeKid].Add(overlap)
+	for kid, key := range ks.keys {
+		if kid != activeKid {
+			key.NotAfter = retireAt
+		}
+	}
+
+	return ks, nil
+}
+
+func loadSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodRS256, Private: key}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodEdDSA, Private: key}, nil
+	default:
+		return nil, fmt.Errorf("%s: only RSA and Ed25519 keys are supported", path)
+	}
+}
+
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.hmacSecret)
+	}
+
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.Kid
+	return token.SignedString(ks.active.Private)
+}
+
+// Parse verifies the signature with the key named by the kid header, or with
+// the legacy HMAC secret for tokens without one, and fills claims.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, ks.keyFunc, jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (ks *KeySet) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok || ks.hmacSecret == nil {
+			return nil, errors.New("unexpected signing method")
+		}
+		return ks.hmacSecret, nil
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if !key.NotAfter.IsZero() && time.Now().After(key.NotAfter) {
+		return nil, fmt.Errorf("key %q has been retired", kid)
+	}
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.Private.Public(), nil
+}
+
+// JWKS lists the public half of every key that is still accepted.
+func (ks *KeySet) JWKS() []JSONWebKey {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	now := time.Now()
+	jwks := make([]JSONWebKey, 0, len(kids))
+	for _, kid := range kids {
+		key := ks.keys[kid]
+		if !key.NotAfter.IsZero() && now.After(key.NotAfter) {
+			continue
+		}
+
+		switch pub := key.Private.Public().(type) {
+		case *rsa.PublicKey:
+			jwks = append(jwks, JSONWebKey{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks = append(jwks, JSONWebKey{
+				Kty: "OKP",
+				Kid: kid,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return jwks
+}
@@ -0,0 +1,114 @@
+package helper
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKey(t *testing.T, dir string, kid string, key interface{}, modTime time.Time) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, kid+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "2025-01", edKey, time.Now().Add(-48*time.Hour))
+	writeKey(t, dir, "2025-02", rsaKey, time.Now().Add(-time.Hour))
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{"sub": "admin", "exp": time.Now().Add(time.Minute).Unix()}
+	}
+
+	t.Run("Newest key signs with kid header", func(t *testing.T) {
+		ks, err := LoadKeySet(dir, "", 24*time.Hour, "")
+		assert.NoError(t, err)
+
+		signed, err := ks.Sign(claims())
+		assert.NoError(t, err)
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(signed, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "2025-02", parsed.Header["kid"])
+		assert.Equal(t, "RS256", parsed.Header["alg"])
+		assert.NoError(t, ks.Parse(signed, jwt.MapClaims{}))
+	})
+
+	t.Run("Old key verifies during overlap", func(t *testing.T) {
+		old, err := LoadKeySet(dir, "2025-01", 24*time.Hour, "")
+		assert.NoError(t, err)
+		signed, err := old.Sign(claims())
+		assert.NoError(t, err)
+
+		ks, err := LoadKeySet(dir, "2025-02", 24*time.Hour, "")
+		assert.NoError(t, err)
+		assert.NoError(t, ks.Parse(signed, jwt.MapClaims{}))
+
+		retired, err := LoadKeySet(dir, "2025-02", 30*time.Minute, "")
+		assert.NoError(t, err)
+		assert.ErrorIs(t, retired.Parse(signed, jwt.MapClaims{}), ErrInvalidToken)
+		assert.Len(t, retired.JWKS(), 1)
+	})
+
+	t.Run("Legacy HMAC tokens only with secret", func(t *testing.T) {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims()).SignedString([]byte("legacy"))
+		assert.NoError(t, err)
+
+		withSecret, err := LoadKeySet(dir, "", 24*time.Hour, "legacy")
+		assert.NoError(t, err)
+		assert.NoError(t, withSecret.Parse(signed, jwt.MapClaims{}))
+
+		withoutSecret, err := LoadKeySet(dir, "", 24*time.Hour, "")
+		assert.NoError(t, err)
+		assert.ErrorIs(t, withoutSecret.Parse(signed, jwt.MapClaims{}), ErrInvalidToken)
+	})
+
+	t.Run("JWKS publishes public keys", func(t *testing.T) {
+		ks, err := LoadKeySet(dir, "", 24*time.Hour, "")
+		assert.NoError(t, err)
+
+		jwks := ks.JWKS()
+		assert.Len(t, jwks, 2)
+		assert.Equal(t, "OKP", jwks[0].Kty)
+		assert.Equal(t, "EdDSA", jwks[0].Alg)
+		assert.Equal(t, "Ed25519", jwks[0].Crv)
+		assert.NotEmpty(t, jwks[0].X)
+		assert.Equal(t, "RSA", jwks[1].Kty)
+		assert.Equal(t, "AQAB", jwks[1].E)
+		assert.NotEmpty(t, jwks[1].N)
+	})
+}
+
+func TestLoadKeySetRequiresConfiguration(t *testing.T) {
+	_, err := LoadKeySet("", "", time.Hour, "")
+	assert.Error(t, err)
+
+	_, err = LoadKeySet(t.TempDir(), "", time.Hour, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package helper
+
+import (
+	"sync"
+	"time"
+)
+
+const maxTrackedLoginKeys = 10000
+
+type LoginLimiter struct {
+	mu          sync.Mutex
+	attempts    map[string]*loginAttempt
+	maxAttempts int
+	baseLockout time.Duration
+	maxLockout  time.Duration
+	window      time.Duration
+}
+
+type loginAttempt struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// NewLoginLimiter locks a key out after maxAttempts failures, doubling the
+// lockout from baseLockout for every further failure up to maxLockout.
+// Failures are forgotten once a key has been quiet for window.
+func NewLoginLimiter(maxAttempts int, baseLockout time.Duration, maxLockout time.Duration, window time.Duration) *LoginLimiter {
+	return &LoginLimiter{
+		attempts:    make(map[string]*loginAttempt),
+		maxAttempts: maxAttempts,
+		baseLockout: baseLockout,
+		maxLockout:  maxLockout,
+		window:      window,
+	}
+}
+
+// Check returns how long key still has to wait before it may try again.
+func (l *LoginLimiter) Check(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	attempt, ok := l.attempts[key]
+	if !ok {
+		return 0
+	}
+
+	now := time.Now()
+	if l.expired(attempt, now) {
+		delete(l.attempts, key)
+		return 0
+	}
+
+	if now.Before(attempt.lockedUntil) {
+		return attempt.lockedUntil.Sub(now)
+	}
+	return 0
+}
+
+// Fail records a failed attempt and returns the lockout it triggered, if any.
+func (l *LoginLimiter) Fail(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.attempts) >= maxTrackedLoginKeys {
+		l.sweep(now)
+	}
+
+	attempt, ok := l.attempts[key]
+	if !ok || l.expired(attempt, now) {
+		attempt = &loginAttempt{}
+		l.attempts[key] = attempt
+	}
+
+	attempt.failures++
+	attempt.lastFailure = now
+	if attempt.failures < l.maxAttempts {
+		return 0
+	}
+
+	lockout := l.baseLockout << (attempt.failures - l.maxAttempts)
+	if lockout <= 0 || lockout > l.maxLockout {
+		lockout = l.maxLockout
+	}
+	attempt.lockedUntil = now.Add(lockout)
+	return lockout
+}
+
+func (l *LoginLimiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.attempts, key)
+}
+
+func (l *LoginLimiter) expired(attempt *loginAttempt, now time.Time) bool {
+	return now.After(attempt.lockedUntil) && now.Sub(attempt.lastFailure) > l.window
+}
+
+func (l *LoginLimiter) sweep(now time.Time) {
+	for key, attempt := range l.attempts {
+		if l.expired(attempt, now) {
+			delete(l.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AccessTokenTTL    = 15 * time.Minute
+	RefreshTokenTTL   = 7 * 24 * time.Hour
+	ChallengeTokenTTL = 5 * time.Minute
+
+	TokenTypeAccess    = "access"
+	TokenTypeChallenge = "2fa_challenge"
+)
+
+var ErrInvalidChallengeToken = errors.New("invalid challenge token")
+
+func GenerateAccessToken(adminId uuid.UUID, username string, role string) (string, error) {
+	keys, err := Keys()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		Type:     TokenTypeAccess,
+		Username: username,
+		Roles:    []string{role},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   adminId.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+		},
+	}
+
+	return keys.Sign(claims)
+}
+
+// ParseAccessToken verifies tokenString and rejects tokens that are missing
+// any of the claims the rest of the app relies on.
+func ParseAccessToken(tokenString string) (*Claims, error) {
+	keys, err := Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	if err := keys.Parse(tokenString, claims); err != nil {
+		return nil, err
+	}
+	if claims.Type != TokenTypeAccess || claims.Username == "" || claims.ID == "" || len(claims.Roles) == 0 {
+		return nil, ErrInvalidToken
+	}
+	if _, err := claims.AdminId(); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// GenerateChallengeToken proves the password step of a two-factor login
+// succeeded; it is only accepted by the second login step.
+func GenerateChallengeToken(adminId uuid.UUID) (string, error) {
+	keys, err := Keys()
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{
+		"jti": uuid.NewString(),
+		"typ": TokenTypeChallenge,
+		"sub": adminId.String(),
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(ChallengeTokenTTL).Unix(),
+	}
+
+	return keys.Sign(claims)
+}
+
+func ParseChallengeToken(tokenString string) (uuid.UUID, error) {
+	keys, err := Keys()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	if err := keys.Parse(tokenString, claims); err != nil || claims["typ"] != TokenTypeChallenge {
+		return uuid.Nil, ErrInvalidChallengeToken
+	}
+
+	sub, _ := claims["sub"].(string)
+	id, err := uuid.Parse(sub)
+	if err != nil {
+		return uuid.Nil, ErrInvalidChallengeToken
+	}
+
+	return id, nil
+}
+
+// GenerateRefreshToken returns an opaque token for the client and the hash
+// that is stored server side; the raw token is never persisted.
+func GenerateRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	// RFC 6238 appendix B SHA1 secret.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		name     string
+		code     string
+		at       time.Time
+		expected bool
+	}{
+		{name: "RFC vector 59", code: "287082", at: time.Unix(59, 0), expected: true},
+		{name: "RFC vector 1111111109", code: "081804", at: time.Unix(1111111109, 0), expected: true},
+		{name: "Previous step within skew", code: "081804", at: time.Unix(1111111109+30, 0), expected: true},
+		{name: "Outside skew", code: "081804", at: time.Unix(1111111109+90, 0), expected: false},
+		{name: "Wrong code", code: "000000", at: time.Unix(59, 0), expected: false},
+		{name: "Wrong length", code: "28708", at: time.Unix(59, 0), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := ValidateTOTP(secret, tt.code, tt.at)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	assert.Equal(t, "abcde-fghij", NormalizeRecoveryCode(" ABCDE-FGHIJ "))
+	assert.Equal(t, "abcde-fghij", NormalizeRecoveryCode("abcdefghij"))
+}
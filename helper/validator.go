@@ -1,19 +1,41 @@
 package helper
 
 import (
+	"catering-admin-go/domain"
+	"reflect"
+	"strings"
 	"sync"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
 )
 
 var (
-	validate *validator.Validate
-	once     sync.Once
+	validate   *validator.Validate
+	translator *ut.UniversalTranslator
+	once       sync.Once
 )
 
 func Validator() *validator.Validate {
 	once.Do(func() {
 		validate = validator.New()
+		validate.RegisterTagNameFunc(fieldName)
+
+		english := en.New()
+		translator = ut.New(english, english, id.New())
+
+		enTrans, _ := translator.GetTranslator("en")
+		if err := enTranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+			panic(err)
+		}
+		idTrans, _ := translator.GetTranslator("id")
+		if err := idTranslations.RegisterDefaultTranslations(validate, idTrans); err != nil {
+			panic(err)
+		}
 	})
 	return validate
 }
@@ -21,3 +43,51 @@ func Validator() *validator.Validate {
 func ValidateStruct(s interface{}) error {
 	return Validator().Struct(s)
 }
+
+// TranslateValidationErrors describes each failed rule in the best language
+// from an Accept-Language header, falling back to English.
+func TranslateValidationErrors(errs validator.ValidationErrors, acceptLanguage string) []domain.FieldError {
+	Validator()
+	trans, _ := translator.FindTranslator(acceptedLocales(acceptLanguage)...)
+
+	fields := make([]domain.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, domain.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: fe.Translate(trans),
+		})
+	}
+	return fields
+}
+
+// fieldName reports fields by the name the client sent them under.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// acceptedLocales lists the languages of an Accept-Language header in the
+// order given, adding the base language after each regional one.
+func acceptedLocales(header string) []string {
+	var locales []string
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		locales = append(locales, strings.ReplaceAll(tag, "-", "_"))
+		if base, _, found := strings.Cut(tag, "-"); found {
+			locales = append(locales, base)
+		}
+	}
+	return locales
+}
@@ -6,22 +6,27 @@ package main
 import (
 	"catering-admin-go/controller"
 	"catering-admin-go/helper"
+	"catering-admin-go/middleware"
 	"catering-admin-go/repository"
 	"catering-admin-go/service"
+	"catering-admin-go/storage"
 
-	"github.com/gofiber/fiber/v2"
 	"github.com/google/wire"
 )
 
 var ServerSet = wire.NewSet(
 	repository.NewRepositoryImpl,
 	service.NewServiceImpl,
+	service.NewTrashPurger,
+	storage.NewLocalStorage,
 	controller.NewControllerImpl,
+	middleware.NewMiddlewareImpl,
 	helper.NewDb,
 	NewServer,
+	wire.Struct(new(Server), "*"),
 )
 
-func InitServer() (*fiber.App, func(), error) {
+func InitServer() (*Server, func(), error) {
 	wire.Build(ServerSet)
 	return nil, nil, nil
 }
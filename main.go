@@ -2,44 +2,116 @@ package main
 
 import (
 	"catering-admin-go/controller"
+	"catering-admin-go/helper"
 	"catering-admin-go/middleware"
+	"catering-admin-go/service"
+	"catering-admin-go/storage"
+	"catering-admin-go/web"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func NewServer(handler controller.Controller) *fiber.App {
-	app := fiber.New()
+// maxBodySize fits a full batch of product images plus the form fields.
+const maxBodySize = service.MaxImagesPerWrite*service.MaxImageSize + 1<<20
+
+// Server is what main runs: the HTTP app and the jobs started next to it.
+type Server struct {
+	App    *fiber.App
+	Purger *service.TrashPurger
+}
+
+func NewServer(handler controller.Controller, mw middleware.Middleware, store storage.Storage) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: web.ErrorHandler,
+		BodyLimit:    maxBodySize,
+	})
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000",
 		AllowCredentials: true,
-		AllowHeaders:     "Origin, Content-type, Accept, Authorization",
-		AllowMethods:     "GET, POST, PUT, DELETE",
+		AllowHeaders:     "Origin, Content-type, Accept, Authorization, If-Match",
+		ExposeHeaders:    "ETag",
+		AllowMethods:     "GET, POST, PUT, PATCH, DELETE",
 	}))
 
+	if local, ok := store.(*storage.LocalStorage); ok {
+		app.Static(local.URLPrefix, local.Root)
+	}
+
+	app.Get("/.well-known/jwks.json", handler.GetJWKS)
 	app.Post("/v1/login", handler.Login)
+	app.Post("/v1/login/2fa", handler.VerifyTwoFactor)
+	app.Post("/v1/token/refresh", handler.RefreshToken)
+	app.Post("/v1/logout", mw.MyMiddleware, handler.Logout)
 
 	protectedRoute := app.Group("/api")
-	protectedRoute.Use(middleware.MyMiddleware)
-	protectedRoute.Get("/v1/orders", handler.GetOrders)
-	protectedRoute.Put("/v1/orders/:id", handler.UpdateOrder)
-	protectedRoute.Delete("/v1/orders/:id", handler.DeleteOrder)
+	protectedRoute.Use(mw.MyMiddleware)
+	protectedRoute.Get("/v1/orders", mw.RequirePermission(middleware.PermissionOrdersRead), handler.GetOrders)
+	protectedRoute.Get("/v1/orders/:id", mw.RequirePermission(middleware.PermissionOrdersRead), handler.GetOrder)
+	protectedRoute.Put("/v1/orders/:id", mw.RequirePermission(middleware.PermissionOrdersUpdate), handler.UpdateOrder)
+	protectedRoute.Delete("/v1/orders/:id", mw.RequirePermission(middleware.PermissionOrdersDelete), handler.DeleteOrder)
+
+	protectedRoute.Post("/v1/products", mw.RequirePermission(middleware.PermissionProductsWrite), handler.AddProduct)
+	protectedRoute.Get("/v1/products", mw.RequirePermission(middleware.PermissionProductsRead), handler.GetProducts)
+	protectedRoute.Get("/v1/products/trash", mw.RequirePermission(middleware.PermissionProductsDelete), handler.GetProductTrash)
+	protectedRoute.Get("/v1/products/:id", mw.RequirePermission(middleware.PermissionProductsRead), handler.GetProduct)
+	protectedRoute.Post("/v1/products/:id/restore", mw.RequirePermission(middleware.PermissionProductsDelete), handler.RestoreProduct)
+	protectedRoute.Delete("/v1/products/:id", mw.RequirePermission(middleware.PermissionProductsDelete), handler.DeleteProduct)
+	protectedRoute.Patch("/v1/products/:id", mw.RequirePermission(middleware.PermissionProductsWrite), handler.UpdateProduct)
+	protectedRoute.Put("/v1/products/:id", mw.RequirePermission(middleware.PermissionProductsWrite), handler.UpdateProduct)
+	protectedRoute.Delete("/v1/products/:id/images/:imageId", mw.RequirePermission(middleware.PermissionProductsWrite), handler.DeleteProductImage)
+	protectedRoute.Get("/v1/products/:id/stock-movements", mw.RequirePermission(middleware.PermissionProductsRead), handler.GetStockMovements)
+	protectedRoute.Post("/v1/products/:id/stock-movements", mw.RequirePermission(middleware.PermissionProductsWrite), handler.AddStockMovement)
+	protectedRoute.Post("/v1/products/:id/variants", mw.RequirePermission(middleware.PermissionProductsWrite), handler.AddProductVariant)
+	protectedRoute.Put("/v1/products/:id/variants/:variantId", mw.RequirePermission(middleware.PermissionProductsWrite), handler.UpdateProductVariant)
+	protectedRoute.Delete("/v1/products/:id/variants/:variantId", mw.RequirePermission(middleware.PermissionProductsWrite), handler.DeleteProductVariant)
+	protectedRoute.Post("/v1/products/:id/option-groups", mw.RequirePermission(middleware.PermissionProductsWrite), handler.AddOptionGroup)
+	protectedRoute.Put("/v1/products/:id/option-groups/:groupId", mw.RequirePermission(middleware.PermissionProductsWrite), handler.UpdateOptionGroup)
+	protectedRoute.Delete("/v1/products/:id/option-groups/:groupId", mw.RequirePermission(middleware.PermissionProductsWrite), handler.DeleteOptionGroup)
+	protectedRoute.Post("/v1/products/:id/option-groups/:groupId/options", mw.RequirePermission(middleware.PermissionProductsWrite), handler.AddProductOption)
+	protectedRoute.Put("/v1/products/:id/option-groups/:groupId/options/:optionId", mw.RequirePermission(middleware.PermissionProductsWrite), handler.UpdateProductOption)
+	protectedRoute.Delete("/v1/products/:id/option-groups/:groupId/options/:optionId", mw.RequirePermission(middleware.PermissionProductsWrite), handler.DeleteProductOption)
 
-	protectedRoute.Post("/v1/products", handler.AddProduct)
-	protectedRoute.Get("/v1/products", handler.GetProducts)
-	protectedRoute.Delete("/v1/products/:id", handler.DeleteProduct)
-	protectedRoute.Put("/v1/products/:id", handler.UpdateProduct)
+	protectedRoute.Get("/v1/packages", mw.RequirePermission(middleware.PermissionProductsRead), handler.GetPackages)
+	protectedRoute.Get("/v1/packages/:id", mw.RequirePermission(middleware.PermissionProductsRead), handler.GetPackage)
+	protectedRoute.Post("/v1/packages", mw.RequirePermission(middleware.PermissionProductsWrite), handler.AddPackage)
+	protectedRoute.Put("/v1/packages/:id", mw.RequirePermission(middleware.PermissionProductsWrite), handler.UpdatePackage)
+	protectedRoute.Delete("/v1/packages/:id", mw.RequirePermission(middleware.PermissionProductsDelete), handler.DeletePackage)
+
+	protectedRoute.Get("/v1/categories", mw.RequirePermission(middleware.PermissionProductsRead), handler.GetCategories)
+	protectedRoute.Get("/v1/categories/:id", mw.RequirePermission(middleware.PermissionProductsRead), handler.GetCategory)
+	protectedRoute.Post("/v1/categories", mw.RequirePermission(middleware.PermissionProductsWrite), handler.AddCategory)
+	protectedRoute.Put("/v1/categories/:id", mw.RequirePermission(middleware.PermissionProductsWrite), handler.UpdateCategory)
+	protectedRoute.Delete("/v1/categories/:id", mw.RequirePermission(middleware.PermissionProductsDelete), handler.DeleteCategory)
+
+	protectedRoute.Put("/v1/me/password", handler.ChangePassword)
+	protectedRoute.Post("/v1/me/2fa/setup", handler.SetupTwoFactor)
+	protectedRoute.Post("/v1/me/2fa/enable", handler.EnableTwoFactor)
+	protectedRoute.Post("/v1/me/2fa/disable", handler.DisableTwoFactor)
+	protectedRoute.Get("/v1/admins", mw.RequirePermission(middleware.PermissionAdminsManage), handler.GetAdmins)
+	protectedRoute.Post("/v1/admins", mw.RequirePermission(middleware.PermissionAdminsManage), handler.AddAdmin)
+	protectedRoute.Put("/v1/admins/:id/disable", mw.RequirePermission(middleware.PermissionAdminsManage), handler.DisableAdmin)
+	protectedRoute.Put("/v1/admins/:id/enable", mw.RequirePermission(middleware.PermissionAdminsManage), handler.EnableAdmin)
+	protectedRoute.Put("/v1/admins/:id/password", mw.RequirePermission(middleware.PermissionAdminsManage), handler.ResetAdminPassword)
+	protectedRoute.Delete("/v1/admins/:id", mw.RequirePermission(middleware.PermissionAdminsManage), handler.DeleteAdmin)
 
 	return app
 }
 
 func main() {
-	app, cleanup, err := InitServer()
+	if _, err := helper.Keys(); err != nil {
+		panic(err)
+	}
+
+	server, cleanup, err := InitServer()
 	if err != nil {
 		panic(err)
 	}
 
 	defer cleanup()
 
-	app.Listen(":8080")
+	stopPurger := server.Purger.Start()
+	defer stopPurger()
+
+	server.App.Listen(":8080")
 }
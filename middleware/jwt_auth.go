@@ -1,16 +1,23 @@
 package middleware
 
 import (
-	"errors"
-	"os"
+	"catering-admin-go/helper"
+	"catering-admin-go/service"
+	"catering-admin-go/web"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func MyMiddleware(c *fiber.Ctx) error {
+type MiddlewareImpl struct {
+	svc service.Service
+}
+
+func NewMiddlewareImpl(svc service.Service) Middleware {
+	return &MiddlewareImpl{svc: svc}
+}
+
+func (mw *MiddlewareImpl) MyMiddleware(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
@@ -22,40 +29,45 @@ func MyMiddleware(c *fiber.Ctx) error {
 	}
 
 	tokenString := splitHeader[1]
-	secret := []byte(os.Getenv("JWT_SECRET"))
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return secret, nil
-	})
-
-	if err != nil || !token.Valid {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid claims"})
+	claims, err := helper.ParseAccessToken(tokenString)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token"})
 	}
 
-	username, ok := claims["username"].(string)
-	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid claims username"})
+	adminId, err := claims.AdminId()
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token"})
 	}
 
-	exp, ok := claims["exp"].(float64)
-	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid expiration claim"})
+	revoked, err := mw.svc.IsTokenRevoked(c.UserContext(), claims.ID, adminId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Unable to verify token"})
 	}
-
-	expTime := time.Unix(int64(exp), 0)
-	if time.Now().After(expTime) {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Expired token"})
+	if revoked {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Revoked token"})
 	}
 
-	c.Locals("token", tokenString)
-	c.Locals("username", username)
+	c.SetUserContext(helper.WithClaims(c.UserContext(), claims))
 
 	return c.Next()
 }
+
+// RequirePermission must run after MyMiddleware so the claims are present.
+func (mw *MiddlewareImpl) RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := helper.ClaimsFromContext(c.UserContext())
+		if !ok || !claimsHavePermission(claims, permission) {
+			return web.ErrorResponse(c, fiber.StatusForbidden, "You do not have permission to perform this action.", "")
+		}
+		return c.Next()
+	}
+}
+
+func claimsHavePermission(claims *helper.Claims, permission string) bool {
+	for _, role := range claims.Roles {
+		if HasPermission(role, permission) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"catering-admin-go/service/mocks"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequirePermission(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	tests := []struct {
+		name           string
+		role           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{
+			name:           "Kitchen can update order status",
+			role:           domain.RoleKitchen,
+			method:         fiber.MethodPut,
+			path:           "/api/v1/orders/1",
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "Kitchen cannot delete product",
+			role:           domain.RoleKitchen,
+			method:         fiber.MethodDelete,
+			path:           "/api/v1/products/PRD001",
+			expectedStatus: fiber.StatusForbidden,
+		},
+		{
+			name:           "Owner can delete product",
+			role:           domain.RoleOwner,
+			method:         fiber.MethodDelete,
+			path:           "/api/v1/products/PRD001",
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "Unknown role is forbidden",
+			role:           "guest",
+			method:         fiber.MethodPut,
+			path:           "/api/v1/orders/1",
+			expectedStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mocks.NewService(t)
+			svc.On("IsTokenRevoked", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(false, nil)
+			mw := NewMiddlewareImpl(svc)
+
+			ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+			app := fiber.New()
+			api := app.Group("/api", mw.MyMiddleware)
+			api.Put("/v1/orders/:id", mw.RequirePermission(PermissionOrdersUpdate), ok)
+			api.Delete("/v1/products/:id", mw.RequirePermission(PermissionProductsDelete), ok)
+
+			token, err := helper.GenerateAccessToken(uuid.New(), "admin", tt.role)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestMyMiddlewareRevokedToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	svc := mocks.NewService(t)
+	svc.On("IsTokenRevoked", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(true, nil)
+	mw := NewMiddlewareImpl(svc)
+
+	app := fiber.New()
+	app.Get("/api/v1/orders", mw.MyMiddleware, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	token, err := helper.GenerateAccessToken(uuid.New(), "admin", domain.RoleOwner)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/api/v1/orders", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestMyMiddlewareDisabledAdmin(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	disabledId := uuid.New()
+	svc := mocks.NewService(t)
+	svc.On("IsTokenRevoked", mock.Anything, mock.AnythingOfType("string"), disabledId).Return(true, nil)
+	mw := NewMiddlewareImpl(svc)
+
+	app := fiber.New()
+	app.Get("/api/v1/orders", mw.MyMiddleware, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	// The token was issued before the admin was disabled and has not expired.
+	token, err := helper.GenerateAccessToken(disabledId, "admin", domain.RoleOwner)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/api/v1/orders", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestMyMiddlewareSetsClaims(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	svc := mocks.NewService(t)
+	svc.On("IsTokenRevoked", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(false, nil)
+	mw := NewMiddlewareImpl(svc)
+
+	adminId := uuid.New()
+	var claims *helper.Claims
+	app := fiber.New()
+	app.Get("/api/v1/orders", mw.MyMiddleware, func(c *fiber.Ctx) error {
+		claims, _ = helper.ClaimsFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	token, err := helper.GenerateAccessToken(adminId, "admin", domain.RoleCashier)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/api/v1/orders", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	if assert.NotNil(t, claims) {
+		assert.Equal(t, adminId.String(), claims.Subject)
+		assert.Equal(t, "admin", claims.Username)
+		assert.Equal(t, []string{domain.RoleCashier}, claims.Roles)
+		assert.NotEmpty(t, claims.ID)
+		assert.NotNil(t, claims.IssuedAt)
+	}
+}
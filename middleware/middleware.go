@@ -0,0 +1,8 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+type Middleware interface {
+	MyMiddleware(c *fiber.Ctx) error
+	RequirePermission(permission string) fiber.Handler
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import "catering-admin-go/domain"
+
+const (
+	PermissionOrdersRead     = "orders:read"
+	PermissionOrdersUpdate   = "orders:update"
+	PermissionOrdersDelete   = "orders:delete"
+	PermissionProductsRead   = "products:read"
+	PermissionProductsWrite  = "products:write"
+	PermissionProductsDelete = "products:delete"
+	PermissionAdminsManage   = "admins:manage"
+)
+
+var rolePermissions = map[string][]string{
+	domain.RoleOwner: {
+		PermissionOrdersRead, PermissionOrdersUpdate, PermissionOrdersDelete,
+		PermissionProductsRead, PermissionProductsWrite, PermissionProductsDelete,
+		PermissionAdminsManage,
+	},
+	domain.RoleManager: {
+		PermissionOrdersRead, PermissionOrdersUpdate, PermissionOrdersDelete,
+		PermissionProductsRead, PermissionProductsWrite, PermissionProductsDelete,
+	},
+	domain.RoleKitchen: {
+		PermissionOrdersRead, PermissionOrdersUpdate,
+		PermissionProductsRead,
+	},
+	domain.RoleCashier: {
+		PermissionOrdersRead, PermissionOrdersUpdate,
+		PermissionProductsRead,
+	},
+}
+
+func HasPermission(role string, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
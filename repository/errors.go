@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"catering-admin-go/domain"
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	mysqlErrBadNull           = 1048
+	mysqlErrDuplicateEntry    = 1062
+	mysqlErrDataTooLong       = 1406
+	mysqlErrRowIsReferenced   = 1451
+	mysqlErrNoReferencedRow   = 1452
+	mysqlErrCheckConstraint   = 3819
+	mysqlErrTruncatedWrongVal = 1366
+)
+
+// translateError turns driver errors the client can do something about into
+// domain errors for entity and passes everything else through unchanged.
+func translateError(entity string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewError(domain.KindNotFound, entity, err)
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+
+	switch mysqlErr.Number {
+	case mysqlErrDuplicateEntry:
+		return domain.NewError(domain.KindConflict, entity, err)
+	case mysqlErrRowIsReferenced:
+		return domain.NewError(domain.KindForeignKeyInUse, entity, err)
+	case mysqlErrNoReferencedRow, mysqlErrBadNull, mysqlErrDataTooLong, mysqlErrCheckConstraint, mysqlErrTruncatedWrongVal:
+		return domain.NewError(domain.KindValidation, entity, err)
+	default:
+		return err
+	}
+}
+
+// translateProductError reports a product that points at a missing category
+// as a category error; category_id is the only foreign key on products.
+func translateProductError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrNoReferencedRow {
+		return &domain.Error{Kind: domain.KindValidation, Entity: "category", Message: "Category does not exist.", Err: err}
+	}
+	return translateError("product", err)
+}
@@ -5,10 +5,11 @@ package mocks
 import (
 	domain "catering-admin-go/domain"
 	context "context"
+	sql "database/sql"
+	uuid "github.com/google/uuid"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
-
-	sql "database/sql"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -16,6 +17,120 @@ type Repository struct {
 	mock.Mock
 }
 
+// AddAdmin provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) AddAdmin(ctx context.Context, tx *sql.Tx, entity *domain.Admin) (*domain.Admin, error) {
+	ret := _m.Called(ctx, tx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAdmin")
+	}
+
+	var r0 *domain.Admin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Admin) (*domain.Admin, error)); ok {
+		return rf(ctx, tx, entity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Admin) *domain.Admin); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Admin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Admin) error); ok {
+		r1 = rf(ctx, tx, entity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddCategory provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) AddCategory(ctx context.Context, tx *sql.Tx, entity *domain.Category) (*domain.Category, error) {
+	ret := _m.Called(ctx, tx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCategory")
+	}
+
+	var r0 *domain.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Category) (*domain.Category, error)); ok {
+		return rf(ctx, tx, entity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Category) *domain.Category); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Category) error); ok {
+		r1 = rf(ctx, tx, entity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddOptionGroup provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) AddOptionGroup(ctx context.Context, tx *sql.Tx, entity *domain.OptionGroup) error {
+	ret := _m.Called(ctx, tx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddOptionGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.OptionGroup) error); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddOrderStatusChange provides a mock function with given fields: ctx, tx, change
+func (_m *Repository) AddOrderStatusChange(ctx context.Context, tx *sql.Tx, change *domain.OrderStatusChange) error {
+	ret := _m.Called(ctx, tx, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddOrderStatusChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.OrderStatusChange) error); ok {
+		r0 = rf(ctx, tx, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddPackage provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) AddPackage(ctx context.Context, tx *sql.Tx, entity *domain.Package) error {
+	ret := _m.Called(ctx, tx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddPackage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Package) error); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddProduct provides a mock function with given fields: ctx, tx, entity
 func (_m *Repository) AddProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain) (*domain.Domain, error) {
 	ret := _m.Called(ctx, tx, entity)
@@ -46,17 +161,17 @@ func (_m *Repository) AddProduct(ctx context.Context, tx *sql.Tx, entity *domain
 	return r0, r1
 }
 
-// DeleteOrder provides a mock function with given fields: ctx, tx, id
-func (_m *Repository) DeleteOrder(ctx context.Context, tx *sql.Tx, id string) error {
-	ret := _m.Called(ctx, tx, id)
+// AddProductImage provides a mock function with given fields: ctx, tx, image
+func (_m *Repository) AddProductImage(ctx context.Context, tx *sql.Tx, image *domain.ProductImage) error {
+	ret := _m.Called(ctx, tx, image)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteOrder")
+		panic("no return value specified for AddProductImage")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) error); ok {
-		r0 = rf(ctx, tx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.ProductImage) error); ok {
+		r0 = rf(ctx, tx, image)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -64,17 +179,17 @@ func (_m *Repository) DeleteOrder(ctx context.Context, tx *sql.Tx, id string) er
 	return r0
 }
 
-// DeleteProduct provides a mock function with given fields: ctx, tx, id
-func (_m *Repository) DeleteProduct(ctx context.Context, tx *sql.Tx, id string) error {
-	ret := _m.Called(ctx, tx, id)
+// AddProductOption provides a mock function with given fields: ctx, tx, productId, entity
+func (_m *Repository) AddProductOption(ctx context.Context, tx *sql.Tx, productId string, entity *domain.ProductOption) error {
+	ret := _m.Called(ctx, tx, productId, entity)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteProduct")
+		panic("no return value specified for AddProductOption")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) error); ok {
-		r0 = rf(ctx, tx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, *domain.ProductOption) error); ok {
+		r0 = rf(ctx, tx, productId, entity)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -82,29 +197,99 @@ func (_m *Repository) DeleteProduct(ctx context.Context, tx *sql.Tx, id string)
 	return r0
 }
 
-// GetOrders provides a mock function with given fields: ctx, db
-func (_m *Repository) GetOrders(ctx context.Context, db *sql.DB) ([]*domain.Orders, error) {
-	ret := _m.Called(ctx, db)
+// AddProductVariant provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) AddProductVariant(ctx context.Context, tx *sql.Tx, entity *domain.ProductVariant) error {
+	ret := _m.Called(ctx, tx, entity)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOrders")
+		panic("no return value specified for AddProductVariant")
 	}
 
-	var r0 []*domain.Orders
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.ProductVariant) error); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddRefreshToken provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) AddRefreshToken(ctx context.Context, tx *sql.Tx, entity *domain.RefreshToken) error {
+	ret := _m.Called(ctx, tx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.RefreshToken) error); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddRevokedAccessToken provides a mock function with given fields: ctx, tx, jti, expiresAt
+func (_m *Repository) AddRevokedAccessToken(ctx context.Context, tx *sql.Tx, jti string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, tx, jti, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRevokedAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, time.Time) error); ok {
+		r0 = rf(ctx, tx, jti, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddStockMovement provides a mock function with given fields: ctx, tx, m
+func (_m *Repository) AddStockMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error {
+	ret := _m.Called(ctx, tx, m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddStockMovement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.StockMovement) error); ok {
+		r0 = rf(ctx, tx, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountOrders provides a mock function with given fields: ctx, db, filter
+func (_m *Repository) CountOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) (int, error) {
+	ret := _m.Called(ctx, db, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOrders")
+	}
+
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) ([]*domain.Orders, error)); ok {
-		return rf(ctx, db)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.OrderFilter) (int, error)); ok {
+		return rf(ctx, db, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) []*domain.Orders); ok {
-		r0 = rf(ctx, db)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.OrderFilter) int); ok {
+		r0 = rf(ctx, db, filter)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Orders)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB) error); ok {
-		r1 = rf(ctx, db)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, *domain.OrderFilter) error); ok {
+		r1 = rf(ctx, db, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -112,29 +297,27 @@ func (_m *Repository) GetOrders(ctx context.Context, db *sql.DB) ([]*domain.Orde
 	return r0, r1
 }
 
-// GetProducts provides a mock function with given fields: ctx, db
-func (_m *Repository) GetProducts(ctx context.Context, db *sql.DB) ([]*domain.Domain, error) {
-	ret := _m.Called(ctx, db)
+// CountProducts provides a mock function with given fields: ctx, db, filter
+func (_m *Repository) CountProducts(ctx context.Context, db *sql.DB, filter *domain.ProductFilter) (int, error) {
+	ret := _m.Called(ctx, db, filter)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetProducts")
+		panic("no return value specified for CountProducts")
 	}
 
-	var r0 []*domain.Domain
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) ([]*domain.Domain, error)); ok {
-		return rf(ctx, db)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.ProductFilter) (int, error)); ok {
+		return rf(ctx, db, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) []*domain.Domain); ok {
-		r0 = rf(ctx, db)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.ProductFilter) int); ok {
+		r0 = rf(ctx, db, filter)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Domain)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB) error); ok {
-		r1 = rf(ctx, db)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, *domain.ProductFilter) error); ok {
+		r1 = rf(ctx, db, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -142,29 +325,27 @@ func (_m *Repository) GetProducts(ctx context.Context, db *sql.DB) ([]*domain.Do
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, db, entity
-func (_m *Repository) Login(ctx context.Context, db *sql.DB, entity *domain.Admin) (*domain.Admin, error) {
-	ret := _m.Called(ctx, db, entity)
+// CountStockMovements provides a mock function with given fields: ctx, db, productId
+func (_m *Repository) CountStockMovements(ctx context.Context, db *sql.DB, productId string) (int, error) {
+	ret := _m.Called(ctx, db, productId)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for CountStockMovements")
 	}
 
-	var r0 *domain.Admin
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.Admin) (*domain.Admin, error)); ok {
-		return rf(ctx, db, entity)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) (int, error)); ok {
+		return rf(ctx, db, productId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.Admin) *domain.Admin); ok {
-		r0 = rf(ctx, db, entity)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) int); ok {
+		r0 = rf(ctx, db, productId)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Admin)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, *domain.Admin) error); ok {
-		r1 = rf(ctx, db, entity)
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string) error); ok {
+		r1 = rf(ctx, db, productId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -172,17 +353,17 @@ func (_m *Repository) Login(ctx context.Context, db *sql.DB, entity *domain.Admi
 	return r0, r1
 }
 
-// UpdateOrder provides a mock function with given fields: ctx, tx, entity, id
-func (_m *Repository) UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id string) error {
-	ret := _m.Called(ctx, tx, entity, id)
+// DeleteAdmin provides a mock function with given fields: ctx, tx, id
+func (_m *Repository) DeleteAdmin(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	ret := _m.Called(ctx, tx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateOrder")
+		panic("no return value specified for DeleteAdmin")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Orders, string) error); ok {
-		r0 = rf(ctx, tx, entity, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -190,34 +371,1310 @@ func (_m *Repository) UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domai
 	return r0
 }
 
-// UpdateProduct provides a mock function with given fields: ctx, tx, entity, id
-func (_m *Repository) UpdateProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain, id string) (*domain.Domain, error) {
-	ret := _m.Called(ctx, tx, entity, id)
+// DeleteCategory provides a mock function with given fields: ctx, tx, id
+func (_m *Repository) DeleteCategory(ctx context.Context, tx *sql.Tx, id int) error {
+	ret := _m.Called(ctx, tx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateProduct")
+		panic("no return value specified for DeleteCategory")
 	}
 
-	var r0 *domain.Domain
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Domain, string) (*domain.Domain, error)); ok {
-		return rf(ctx, tx, entity, id)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, int) error); ok {
+		r0 = rf(ctx, tx, id)
+	} else {
+		r0 = ret.Error(0)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Domain, string) *domain.Domain); ok {
-		r0 = rf(ctx, tx, entity, id)
+
+	return r0
+}
+
+// DeleteOptionGroup provides a mock function with given fields: ctx, tx, productId, id
+func (_m *Repository) DeleteOptionGroup(ctx context.Context, tx *sql.Tx, productId string, id string) error {
+	ret := _m.Called(ctx, tx, productId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOptionGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string) error); ok {
+		r0 = rf(ctx, tx, productId, id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Domain)
-		}
+		r0 = ret.Error(0)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Domain, string) error); ok {
-		r1 = rf(ctx, tx, entity, id)
+	return r0
+}
+
+// DeleteOrder provides a mock function with given fields: ctx, tx, id, version
+func (_m *Repository) DeleteOrder(ctx context.Context, tx *sql.Tx, id string, version int) error {
+	ret := _m.Called(ctx, tx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, int) error); ok {
+		r0 = rf(ctx, tx, id, version)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
+}
+
+// DeletePackage provides a mock function with given fields: ctx, tx, id
+func (_m *Repository) DeletePackage(ctx context.Context, tx *sql.Tx, id string) error {
+	ret := _m.Called(ctx, tx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePackage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) error); ok {
+		r0 = rf(ctx, tx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProduct provides a mock function with given fields: ctx, tx, id, version
+func (_m *Repository) DeleteProduct(ctx context.Context, tx *sql.Tx, id string, version int) error {
+	ret := _m.Called(ctx, tx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, int) error); ok {
+		r0 = rf(ctx, tx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProductImage provides a mock function with given fields: ctx, tx, productId, imageId
+func (_m *Repository) DeleteProductImage(ctx context.Context, tx *sql.Tx, productId string, imageId string) (*domain.ProductImage, error) {
+	ret := _m.Called(ctx, tx, productId, imageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProductImage")
+	}
+
+	var r0 *domain.ProductImage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string) (*domain.ProductImage, error)); ok {
+		return rf(ctx, tx, productId, imageId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string) *domain.ProductImage); ok {
+		r0 = rf(ctx, tx, productId, imageId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductImage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string, string) error); ok {
+		r1 = rf(ctx, tx, productId, imageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteProductOption provides a mock function with given fields: ctx, tx, productId, groupId, id
+func (_m *Repository) DeleteProductOption(ctx context.Context, tx *sql.Tx, productId string, groupId string, id string) error {
+	ret := _m.Called(ctx, tx, productId, groupId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProductOption")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string, string) error); ok {
+		r0 = rf(ctx, tx, productId, groupId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProductVariant provides a mock function with given fields: ctx, tx, productId, id
+func (_m *Repository) DeleteProductVariant(ctx context.Context, tx *sql.Tx, productId string, id string) error {
+	ret := _m.Called(ctx, tx, productId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProductVariant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, string) error); ok {
+		r0 = rf(ctx, tx, productId, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAdminById provides a mock function with given fields: ctx, db, id
+func (_m *Repository) GetAdminById(ctx context.Context, db *sql.DB, id uuid.UUID) (*domain.Admin, error) {
+	ret := _m.Called(ctx, db, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAdminById")
+	}
+
+	var r0 *domain.Admin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, uuid.UUID) (*domain.Admin, error)); ok {
+		return rf(ctx, db, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, uuid.UUID) *domain.Admin); ok {
+		r0 = rf(ctx, db, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Admin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, uuid.UUID) error); ok {
+		r1 = rf(ctx, db, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAdmins provides a mock function with given fields: ctx, db
+func (_m *Repository) GetAdmins(ctx context.Context, db *sql.DB) ([]*domain.Admin, error) {
+	ret := _m.Called(ctx, db)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAdmins")
+	}
+
+	var r0 []*domain.Admin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) ([]*domain.Admin, error)); ok {
+		return rf(ctx, db)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) []*domain.Admin); ok {
+		r0 = rf(ctx, db)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Admin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB) error); ok {
+		r1 = rf(ctx, db)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategories provides a mock function with given fields: ctx, db
+func (_m *Repository) GetCategories(ctx context.Context, db *sql.DB) ([]*domain.Category, error) {
+	ret := _m.Called(ctx, db)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategories")
+	}
+
+	var r0 []*domain.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) ([]*domain.Category, error)); ok {
+		return rf(ctx, db)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) []*domain.Category); ok {
+		r0 = rf(ctx, db)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB) error); ok {
+		r1 = rf(ctx, db)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategory provides a mock function with given fields: ctx, db, id
+func (_m *Repository) GetCategory(ctx context.Context, db *sql.DB, id int) (*domain.Category, error) {
+	ret := _m.Called(ctx, db, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategory")
+	}
+
+	var r0 *domain.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, int) (*domain.Category, error)); ok {
+		return rf(ctx, db, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, int) *domain.Category); ok {
+		r0 = rf(ctx, db, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, int) error); ok {
+		r1 = rf(ctx, db, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOptionGroups provides a mock function with given fields: ctx, db, productId
+func (_m *Repository) GetOptionGroups(ctx context.Context, db *sql.DB, productId string) ([]*domain.OptionGroup, error) {
+	ret := _m.Called(ctx, db, productId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOptionGroups")
+	}
+
+	var r0 []*domain.OptionGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) ([]*domain.OptionGroup, error)); ok {
+		return rf(ctx, db, productId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) []*domain.OptionGroup); ok {
+		r0 = rf(ctx, db, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OptionGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string) error); ok {
+		r1 = rf(ctx, db, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrder provides a mock function with given fields: ctx, db, id
+func (_m *Repository) GetOrder(ctx context.Context, db *sql.DB, id string) (*domain.Orders, error) {
+	ret := _m.Called(ctx, db, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 *domain.Orders
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) (*domain.Orders, error)); ok {
+		return rf(ctx, db, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) *domain.Orders); ok {
+		r0 = rf(ctx, db, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Orders)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string) error); ok {
+		r1 = rf(ctx, db, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderForUpdate provides a mock function with given fields: ctx, tx, id
+func (_m *Repository) GetOrderForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.Orders, error) {
+	ret := _m.Called(ctx, tx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderForUpdate")
+	}
+
+	var r0 *domain.Orders
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) (*domain.Orders, error)); ok {
+		return rf(ctx, tx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) *domain.Orders); ok {
+		r0 = rf(ctx, tx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Orders)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderOptions provides a mock function with given fields: ctx, db, orderIds
+func (_m *Repository) GetOrderOptions(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.OrderOption, error) {
+	ret := _m.Called(ctx, db, orderIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderOptions")
+	}
+
+	var r0 []*domain.OrderOption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, []string) ([]*domain.OrderOption, error)); ok {
+		return rf(ctx, db, orderIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, []string) []*domain.OrderOption); ok {
+		r0 = rf(ctx, db, orderIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OrderOption)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, []string) error); ok {
+		r1 = rf(ctx, db, orderIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderReservedStock provides a mock function with given fields: ctx, tx, orderId
+func (_m *Repository) GetOrderReservedStock(ctx context.Context, tx *sql.Tx, orderId string) ([]*domain.StockLine, error) {
+	ret := _m.Called(ctx, tx, orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderReservedStock")
+	}
+
+	var r0 []*domain.StockLine
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) ([]*domain.StockLine, error)); ok {
+		return rf(ctx, tx, orderId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) []*domain.StockLine); ok {
+		r0 = rf(ctx, tx, orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.StockLine)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderStatusHistory provides a mock function with given fields: ctx, db, orderId
+func (_m *Repository) GetOrderStatusHistory(ctx context.Context, db *sql.DB, orderId string) ([]*domain.OrderStatusChange, error) {
+	ret := _m.Called(ctx, db, orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderStatusHistory")
+	}
+
+	var r0 []*domain.OrderStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) ([]*domain.OrderStatusChange, error)); ok {
+		return rf(ctx, db, orderId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) []*domain.OrderStatusChange); ok {
+		r0 = rf(ctx, db, orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OrderStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string) error); ok {
+		r1 = rf(ctx, db, orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderStockLines provides a mock function with given fields: ctx, tx, orderId
+func (_m *Repository) GetOrderStockLines(ctx context.Context, tx *sql.Tx, orderId string) ([]*domain.StockLine, error) {
+	ret := _m.Called(ctx, tx, orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderStockLines")
+	}
+
+	var r0 []*domain.StockLine
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) ([]*domain.StockLine, error)); ok {
+		return rf(ctx, tx, orderId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) []*domain.StockLine); ok {
+		r0 = rf(ctx, tx, orderId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.StockLine)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrders provides a mock function with given fields: ctx, db, filter
+func (_m *Repository) GetOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) ([]*domain.Orders, error) {
+	ret := _m.Called(ctx, db, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrders")
+	}
+
+	var r0 []*domain.Orders
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.OrderFilter) ([]*domain.Orders, error)); ok {
+		return rf(ctx, db, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.OrderFilter) []*domain.Orders); ok {
+		r0 = rf(ctx, db, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Orders)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, *domain.OrderFilter) error); ok {
+		r1 = rf(ctx, db, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPackage provides a mock function with given fields: ctx, db, id
+func (_m *Repository) GetPackage(ctx context.Context, db *sql.DB, id string) (*domain.Package, error) {
+	ret := _m.Called(ctx, db, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPackage")
+	}
+
+	var r0 *domain.Package
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) (*domain.Package, error)); ok {
+		return rf(ctx, db, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) *domain.Package); ok {
+		r0 = rf(ctx, db, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string) error); ok {
+		r1 = rf(ctx, db, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPackages provides a mock function with given fields: ctx, db
+func (_m *Repository) GetPackages(ctx context.Context, db *sql.DB) ([]*domain.Package, error) {
+	ret := _m.Called(ctx, db)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPackages")
+	}
+
+	var r0 []*domain.Package
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) ([]*domain.Package, error)); ok {
+		return rf(ctx, db)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) []*domain.Package); ok {
+		r0 = rf(ctx, db)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB) error); ok {
+		r1 = rf(ctx, db)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProduct provides a mock function with given fields: ctx, db, id
+func (_m *Repository) GetProduct(ctx context.Context, db *sql.DB, id string) (*domain.Domain, error) {
+	ret := _m.Called(ctx, db, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProduct")
+	}
+
+	var r0 *domain.Domain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) (*domain.Domain, error)); ok {
+		return rf(ctx, db, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) *domain.Domain); ok {
+		r0 = rf(ctx, db, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string) error); ok {
+		r1 = rf(ctx, db, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProductImages provides a mock function with given fields: ctx, db, productIds
+func (_m *Repository) GetProductImages(ctx context.Context, db *sql.DB, productIds []string) ([]*domain.ProductImage, error) {
+	ret := _m.Called(ctx, db, productIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductImages")
+	}
+
+	var r0 []*domain.ProductImage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, []string) ([]*domain.ProductImage, error)); ok {
+		return rf(ctx, db, productIds)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, []string) []*domain.ProductImage); ok {
+		r0 = rf(ctx, db, productIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ProductImage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, []string) error); ok {
+		r1 = rf(ctx, db, productIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProductVariants provides a mock function with given fields: ctx, db, productId
+func (_m *Repository) GetProductVariants(ctx context.Context, db *sql.DB, productId string) ([]*domain.ProductVariant, error) {
+	ret := _m.Called(ctx, db, productId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductVariants")
+	}
+
+	var r0 []*domain.ProductVariant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) ([]*domain.ProductVariant, error)); ok {
+		return rf(ctx, db, productId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) []*domain.ProductVariant); ok {
+		r0 = rf(ctx, db, productId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ProductVariant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string) error); ok {
+		r1 = rf(ctx, db, productId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProducts provides a mock function with given fields: ctx, db, filter
+func (_m *Repository) GetProducts(ctx context.Context, db *sql.DB, filter *domain.ProductFilter) ([]*domain.Domain, error) {
+	ret := _m.Called(ctx, db, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProducts")
+	}
+
+	var r0 []*domain.Domain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.ProductFilter) ([]*domain.Domain, error)); ok {
+		return rf(ctx, db, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.ProductFilter) []*domain.Domain); ok {
+		r0 = rf(ctx, db, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, *domain.ProductFilter) error); ok {
+		r1 = rf(ctx, db, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPurgeableProductImages provides a mock function with given fields: ctx, db, cutoff
+func (_m *Repository) GetPurgeableProductImages(ctx context.Context, db *sql.DB, cutoff time.Time) ([]*domain.ProductImage, error) {
+	ret := _m.Called(ctx, db, cutoff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPurgeableProductImages")
+	}
+
+	var r0 []*domain.ProductImage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, time.Time) ([]*domain.ProductImage, error)); ok {
+		return rf(ctx, db, cutoff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, time.Time) []*domain.ProductImage); ok {
+		r0 = rf(ctx, db, cutoff)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ProductImage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, time.Time) error); ok {
+		r1 = rf(ctx, db, cutoff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefreshToken provides a mock function with given fields: ctx, db, tokenHash
+func (_m *Repository) GetRefreshToken(ctx context.Context, db *sql.DB, tokenHash string) (*domain.RefreshToken, error) {
+	ret := _m.Called(ctx, db, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefreshToken")
+	}
+
+	var r0 *domain.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) (*domain.RefreshToken, error)); ok {
+		return rf(ctx, db, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string) *domain.RefreshToken); ok {
+		r0 = rf(ctx, db, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string) error); ok {
+		r1 = rf(ctx, db, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStockMovements provides a mock function with given fields: ctx, db, filter
+func (_m *Repository) GetStockMovements(ctx context.Context, db *sql.DB, filter *domain.StockMovementFilter) ([]*domain.StockMovement, error) {
+	ret := _m.Called(ctx, db, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStockMovements")
+	}
+
+	var r0 []*domain.StockMovement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.StockMovementFilter) ([]*domain.StockMovement, error)); ok {
+		return rf(ctx, db, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.StockMovementFilter) []*domain.StockMovement); ok {
+		r0 = rf(ctx, db, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.StockMovement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, *domain.StockMovementFilter) error); ok {
+		r1 = rf(ctx, db, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsAccessTokenRevoked provides a mock function with given fields: ctx, db, jti, adminId
+func (_m *Repository) IsAccessTokenRevoked(ctx context.Context, db *sql.DB, jti string, adminId uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, db, jti, adminId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAccessTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string, uuid.UUID) (bool, error)); ok {
+		return rf(ctx, db, jti, adminId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, string, uuid.UUID) bool); ok {
+		r0 = rf(ctx, db, jti, adminId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, string, uuid.UUID) error); ok {
+		r1 = rf(ctx, db, jti, adminId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, db, entity
+func (_m *Repository) Login(ctx context.Context, db *sql.DB, entity *domain.Admin) (*domain.Admin, error) {
+	ret := _m.Called(ctx, db, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *domain.Admin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.Admin) (*domain.Admin, error)); ok {
+		return rf(ctx, db, entity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, *domain.Admin) *domain.Admin); ok {
+		r0 = rf(ctx, db, entity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Admin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, *domain.Admin) error); ok {
+		r1 = rf(ctx, db, entity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveStock provides a mock function with given fields: ctx, tx, m
+func (_m *Repository) MoveStock(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error {
+	ret := _m.Called(ctx, tx, m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveStock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.StockMovement) error); ok {
+		r0 = rf(ctx, tx, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NextSequenceValue provides a mock function with given fields: ctx, tx, name
+func (_m *Repository) NextSequenceValue(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+	ret := _m.Called(ctx, tx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextSequenceValue")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) (int64, error)); ok {
+		return rf(ctx, tx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) int64); ok {
+		r0 = rf(ctx, tx, name)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeletedProducts provides a mock function with given fields: ctx, db, cutoff
+func (_m *Repository) PurgeDeletedProducts(ctx context.Context, db *sql.DB, cutoff time.Time) (int64, error) {
+	ret := _m.Called(ctx, db, cutoff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedProducts")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, time.Time) (int64, error)); ok {
+		return rf(ctx, db, cutoff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB, time.Time) int64); ok {
+		r0 = rf(ctx, db, cutoff)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB, time.Time) error); ok {
+		r1 = rf(ctx, db, cutoff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseOrderVariantStock provides a mock function with given fields: ctx, tx, orderId
+func (_m *Repository) ReleaseOrderVariantStock(ctx context.Context, tx *sql.Tx, orderId string) error {
+	ret := _m.Called(ctx, tx, orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseOrderVariantStock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) error); ok {
+		r0 = rf(ctx, tx, orderId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceRecoveryCodes provides a mock function with given fields: ctx, tx, adminId, codeHashes
+func (_m *Repository) ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, adminId uuid.UUID, codeHashes []string) error {
+	ret := _m.Called(ctx, tx, adminId, codeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, []string) error); ok {
+		r0 = rf(ctx, tx, adminId, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveOrderVariantStock provides a mock function with given fields: ctx, tx, orderId
+func (_m *Repository) ReserveOrderVariantStock(ctx context.Context, tx *sql.Tx, orderId string) (string, error) {
+	ret := _m.Called(ctx, tx, orderId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveOrderVariantStock")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) (string, error)); ok {
+		return rf(ctx, tx, orderId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) string); ok {
+		r0 = rf(ctx, tx, orderId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, orderId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreProduct provides a mock function with given fields: ctx, tx, id
+func (_m *Repository) RestoreProduct(ctx context.Context, tx *sql.Tx, id string) (*domain.Domain, error) {
+	ret := _m.Called(ctx, tx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreProduct")
+	}
+
+	var r0 *domain.Domain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) (*domain.Domain, error)); ok {
+		return rf(ctx, tx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) *domain.Domain); ok {
+		r0 = rf(ctx, tx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, string) error); ok {
+		r1 = rf(ctx, tx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAdminRefreshTokens provides a mock function with given fields: ctx, tx, adminId
+func (_m *Repository) RevokeAdminRefreshTokens(ctx context.Context, tx *sql.Tx, adminId uuid.UUID) error {
+	ret := _m.Called(ctx, tx, adminId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAdminRefreshTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID) error); ok {
+		r0 = rf(ctx, tx, adminId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshToken provides a mock function with given fields: ctx, tx, id
+func (_m *Repository) RevokeRefreshToken(ctx context.Context, tx *sql.Tx, id string) error {
+	ret := _m.Called(ctx, tx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) error); ok {
+		r0 = rf(ctx, tx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshTokenFamily provides a mock function with given fields: ctx, tx, familyId
+func (_m *Repository) RevokeRefreshTokenFamily(ctx context.Context, tx *sql.Tx, familyId string) error {
+	ret := _m.Called(ctx, tx, familyId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokenFamily")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string) error); ok {
+		r0 = rf(ctx, tx, familyId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAdminPassword provides a mock function with given fields: ctx, tx, id, password
+func (_m *Repository) UpdateAdminPassword(ctx context.Context, tx *sql.Tx, id uuid.UUID, password string) error {
+	ret := _m.Called(ctx, tx, id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAdminPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, tx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAdminStatus provides a mock function with given fields: ctx, tx, id, disabledAt
+func (_m *Repository) UpdateAdminStatus(ctx context.Context, tx *sql.Tx, id uuid.UUID, disabledAt *time.Time) error {
+	ret := _m.Called(ctx, tx, id, disabledAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAdminStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, *time.Time) error); ok {
+		r0 = rf(ctx, tx, id, disabledAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAdminTOTP provides a mock function with given fields: ctx, tx, id, secret, enabled
+func (_m *Repository) UpdateAdminTOTP(ctx context.Context, tx *sql.Tx, id uuid.UUID, secret string, enabled bool) error {
+	ret := _m.Called(ctx, tx, id, secret, enabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAdminTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, string, bool) error); ok {
+		r0 = rf(ctx, tx, id, secret, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAdminTOTPStep provides a mock function with given fields: ctx, tx, id, step
+func (_m *Repository) UpdateAdminTOTPStep(ctx context.Context, tx *sql.Tx, id uuid.UUID, step int64) error {
+	ret := _m.Called(ctx, tx, id, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAdminTOTPStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, tx, id, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields: ctx, tx, entity, id
+func (_m *Repository) UpdateCategory(ctx context.Context, tx *sql.Tx, entity *domain.Category, id int) (*domain.Category, error) {
+	ret := _m.Called(ctx, tx, entity, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCategory")
+	}
+
+	var r0 *domain.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Category, int) (*domain.Category, error)); ok {
+		return rf(ctx, tx, entity, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Category, int) *domain.Category); ok {
+		r0 = rf(ctx, tx, entity, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.Category, int) error); ok {
+		r1 = rf(ctx, tx, entity, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOptionGroup provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) UpdateOptionGroup(ctx context.Context, tx *sql.Tx, entity *domain.OptionGroup) error {
+	ret := _m.Called(ctx, tx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOptionGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.OptionGroup) error); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateOrder provides a mock function with given fields: ctx, tx, entity, id, version
+func (_m *Repository) UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id string, version int) error {
+	ret := _m.Called(ctx, tx, entity, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Orders, string, int) error); ok {
+		r0 = rf(ctx, tx, entity, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePackage provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) UpdatePackage(ctx context.Context, tx *sql.Tx, entity *domain.Package) error {
+	ret := _m.Called(ctx, tx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePackage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.Package) error); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, tx, patch, id, version
+func (_m *Repository) UpdateProduct(ctx context.Context, tx *sql.Tx, patch *domain.ProductPatch, id string, version int) (*domain.Domain, error) {
+	ret := _m.Called(ctx, tx, patch, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProduct")
+	}
+
+	var r0 *domain.Domain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.ProductPatch, string, int) (*domain.Domain, error)); ok {
+		return rf(ctx, tx, patch, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.ProductPatch, string, int) *domain.Domain); ok {
+		r0 = rf(ctx, tx, patch, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sql.Tx, *domain.ProductPatch, string, int) error); ok {
+		r1 = rf(ctx, tx, patch, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProductOption provides a mock function with given fields: ctx, tx, productId, entity
+func (_m *Repository) UpdateProductOption(ctx context.Context, tx *sql.Tx, productId string, entity *domain.ProductOption) error {
+	ret := _m.Called(ctx, tx, productId, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProductOption")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, string, *domain.ProductOption) error); ok {
+		r0 = rf(ctx, tx, productId, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProductVariant provides a mock function with given fields: ctx, tx, entity
+func (_m *Repository) UpdateProductVariant(ctx context.Context, tx *sql.Tx, entity *domain.ProductVariant) error {
+	ret := _m.Called(ctx, tx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProductVariant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, *domain.ProductVariant) error); ok {
+		r0 = rf(ctx, tx, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, tx, adminId, codeHash
+func (_m *Repository) UseRecoveryCode(ctx context.Context, tx *sql.Tx, adminId uuid.UUID, codeHash string) error {
+	ret := _m.Called(ctx, tx, adminId, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sql.Tx, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, tx, adminId, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -4,15 +4,79 @@ import (
 	"catering-admin-go/domain"
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type Repository interface {
 	Login(ctx context.Context, db *sql.DB, entity *domain.Admin) (*domain.Admin, error)
+	GetAdminById(ctx context.Context, db *sql.DB, id uuid.UUID) (*domain.Admin, error)
+	GetAdmins(ctx context.Context, db *sql.DB) ([]*domain.Admin, error)
+	AddAdmin(ctx context.Context, tx *sql.Tx, entity *domain.Admin) (*domain.Admin, error)
+	UpdateAdminStatus(ctx context.Context, tx *sql.Tx, id uuid.UUID, disabledAt *time.Time) error
+	UpdateAdminPassword(ctx context.Context, tx *sql.Tx, id uuid.UUID, password string) error
+	DeleteAdmin(ctx context.Context, tx *sql.Tx, id uuid.UUID) error
+	UpdateAdminTOTP(ctx context.Context, tx *sql.Tx, id uuid.UUID, secret string, enabled bool) error
+	UpdateAdminTOTPStep(ctx context.Context, tx *sql.Tx, id uuid.UUID, step int64) error
+	ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, adminId uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, tx *sql.Tx, adminId uuid.UUID, codeHash string) error
+	AddRefreshToken(ctx context.Context, tx *sql.Tx, entity *domain.RefreshToken) error
+	GetRefreshToken(ctx context.Context, db *sql.DB, tokenHash string) (*domain.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tx *sql.Tx, id string) error
+	RevokeRefreshTokenFamily(ctx context.Context, tx *sql.Tx, familyId string) error
+	RevokeAdminRefreshTokens(ctx context.Context, tx *sql.Tx, adminId uuid.UUID) error
+	AddRevokedAccessToken(ctx context.Context, tx *sql.Tx, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, db *sql.DB, jti string, adminId uuid.UUID) (bool, error)
+	NextSequenceValue(ctx context.Context, tx *sql.Tx, name string) (int64, error)
 	AddProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain) (*domain.Domain, error)
-	GetProducts(ctx context.Context, db *sql.DB) ([]*domain.Domain, error)
-	DeleteProduct(ctx context.Context, tx *sql.Tx, id string) error
-	UpdateProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain, id string) (*domain.Domain, error)
-	GetOrders(ctx context.Context, db *sql.DB) ([]*domain.Orders, error)
-	UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id string) error
-	DeleteOrder(ctx context.Context, tx *sql.Tx, id string) error
+	GetProduct(ctx context.Context, db *sql.DB, id string) (*domain.Domain, error)
+	GetProducts(ctx context.Context, db *sql.DB, filter *domain.ProductFilter) ([]*domain.Domain, error)
+	CountProducts(ctx context.Context, db *sql.DB, filter *domain.ProductFilter) (int, error)
+	DeleteProduct(ctx context.Context, tx *sql.Tx, id string, version int) error
+	UpdateProduct(ctx context.Context, tx *sql.Tx, patch *domain.ProductPatch, id string, version int) (*domain.Domain, error)
+	MoveStock(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error
+	AddStockMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error
+	CountStockMovements(ctx context.Context, db *sql.DB, productId string) (int, error)
+	GetStockMovements(ctx context.Context, db *sql.DB, filter *domain.StockMovementFilter) ([]*domain.StockMovement, error)
+	RestoreProduct(ctx context.Context, tx *sql.Tx, id string) (*domain.Domain, error)
+	PurgeDeletedProducts(ctx context.Context, db *sql.DB, cutoff time.Time) (int64, error)
+	AddProductImage(ctx context.Context, tx *sql.Tx, image *domain.ProductImage) error
+	GetProductImages(ctx context.Context, db *sql.DB, productIds []string) ([]*domain.ProductImage, error)
+	GetPurgeableProductImages(ctx context.Context, db *sql.DB, cutoff time.Time) ([]*domain.ProductImage, error)
+	DeleteProductImage(ctx context.Context, tx *sql.Tx, productId string, imageId string) (*domain.ProductImage, error)
+	GetProductVariants(ctx context.Context, db *sql.DB, productId string) ([]*domain.ProductVariant, error)
+	AddProductVariant(ctx context.Context, tx *sql.Tx, entity *domain.ProductVariant) error
+	UpdateProductVariant(ctx context.Context, tx *sql.Tx, entity *domain.ProductVariant) error
+	DeleteProductVariant(ctx context.Context, tx *sql.Tx, productId string, id string) error
+	GetOptionGroups(ctx context.Context, db *sql.DB, productId string) ([]*domain.OptionGroup, error)
+	AddOptionGroup(ctx context.Context, tx *sql.Tx, entity *domain.OptionGroup) error
+	UpdateOptionGroup(ctx context.Context, tx *sql.Tx, entity *domain.OptionGroup) error
+	DeleteOptionGroup(ctx context.Context, tx *sql.Tx, productId string, id string) error
+	AddProductOption(ctx context.Context, tx *sql.Tx, productId string, entity *domain.ProductOption) error
+	UpdateProductOption(ctx context.Context, tx *sql.Tx, productId string, entity *domain.ProductOption) error
+	DeleteProductOption(ctx context.Context, tx *sql.Tx, productId string, groupId string, id string) error
+	GetCategories(ctx context.Context, db *sql.DB) ([]*domain.Category, error)
+	GetCategory(ctx context.Context, db *sql.DB, id int) (*domain.Category, error)
+	AddCategory(ctx context.Context, tx *sql.Tx, entity *domain.Category) (*domain.Category, error)
+	UpdateCategory(ctx context.Context, tx *sql.Tx, entity *domain.Category, id int) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, tx *sql.Tx, id int) error
+	GetPackages(ctx context.Context, db *sql.DB) ([]*domain.Package, error)
+	GetPackage(ctx context.Context, db *sql.DB, id string) (*domain.Package, error)
+	AddPackage(ctx context.Context, tx *sql.Tx, entity *domain.Package) error
+	UpdatePackage(ctx context.Context, tx *sql.Tx, entity *domain.Package) error
+	DeletePackage(ctx context.Context, tx *sql.Tx, id string) error
+	GetOrder(ctx context.Context, db *sql.DB, id string) (*domain.Orders, error)
+	GetOrderForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.Orders, error)
+	GetOrderStockLines(ctx context.Context, tx *sql.Tx, orderId string) ([]*domain.StockLine, error)
+	GetOrderReservedStock(ctx context.Context, tx *sql.Tx, orderId string) ([]*domain.StockLine, error)
+	ReserveOrderVariantStock(ctx context.Context, tx *sql.Tx, orderId string) (string, error)
+	ReleaseOrderVariantStock(ctx context.Context, tx *sql.Tx, orderId string) error
+	GetOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) ([]*domain.Orders, error)
+	GetOrderOptions(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.OrderOption, error)
+	CountOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) (int, error)
+	UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id string, version int) error
+	AddOrderStatusChange(ctx context.Context, tx *sql.Tx, change *domain.OrderStatusChange) error
+	GetOrderStatusHistory(ctx context.Context, db *sql.DB, orderId string) ([]*domain.OrderStatusChange, error)
+	DeleteOrder(ctx context.Context, tx *sql.Tx, id string, version int) error
 }
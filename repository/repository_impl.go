@@ -2,127 +2,1510 @@ package repository
 
 import (
 	"catering-admin-go/domain"
+	"catering-admin-go/helper"
 	"catering-admin-go/logger"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
-type RepositoryImpl struct{}
+type RepositoryImpl struct{}
+
+func NewRepositoryImpl() Repository {
+	return &RepositoryImpl{}
+}
+
+func (repo *RepositoryImpl) Login(ctx context.Context, db *sql.DB, entity *domain.Admin) (*domain.Admin, error) {
+	query := "SELECT id, username, password, role, totp_enabled, COALESCE(totp_secret, ''), totp_last_step, disabled_at, created_at, modified_at FROM admin WHERE username = ?"
+	row := db.QueryRowContext(ctx, query, entity.Username)
+
+	var response domain.Admin
+	err := row.Scan(&response.Id, &response.Username, &response.Password, &response.Role, &response.TwoFactorEnabled, &response.TOTPSecret, &response.TOTPLastStep, &response.DisabledAt, &response.CreatedAt, &response.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (repo *RepositoryImpl) GetAdminById(ctx context.Context, db *sql.DB, id uuid.UUID) (*domain.Admin, error) {
+	query := "SELECT id, username, password, role, totp_enabled, COALESCE(totp_secret, ''), totp_last_step, disabled_at, created_at, modified_at FROM admin WHERE id = ?"
+	row := db.QueryRowContext(ctx, query, id)
+
+	var response domain.Admin
+	err := row.Scan(&response.Id, &response.Username, &response.Password, &response.Role, &response.TwoFactorEnabled, &response.TOTPSecret, &response.TOTPLastStep, &response.DisabledAt, &response.CreatedAt, &response.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (repo *RepositoryImpl) GetAdmins(ctx context.Context, db *sql.DB) ([]*domain.Admin, error) {
+	query := "SELECT id, username, role, totp_enabled, disabled_at, created_at, modified_at FROM admin ORDER BY username"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get admins", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var admins []*domain.Admin
+	for rows.Next() {
+		var admin domain.Admin
+		err := rows.Scan(&admin.Id, &admin.Username, &admin.Role, &admin.TwoFactorEnabled, &admin.DisabledAt, &admin.CreatedAt, &admin.ModifiedAt)
+		if err != nil {
+			logger.GetLogger("repository-log").Log("get admins", "error", err.Error())
+			return nil, err
+		}
+		admins = append(admins, &admin)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get admins", "error", err.Error())
+		return nil, err
+	}
+
+	return admins, nil
+}
+
+func (repo *RepositoryImpl) AddAdmin(ctx context.Context, tx *sql.Tx, entity *domain.Admin) (*domain.Admin, error) {
+	query := "INSERT INTO admin(id, username, password, role, created_at) VALUES(?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Username, entity.Password, entity.Role, entity.CreatedAt)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add admin", "error", err.Error())
+		return nil, err
+	}
+
+	return entity, nil
+}
+
+func (repo *RepositoryImpl) UpdateAdminStatus(ctx context.Context, tx *sql.Tx, id uuid.UUID, disabledAt *time.Time) error {
+	query := "UPDATE admin SET disabled_at = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, disabledAt, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update admin status", "error", err.Error())
+		return err
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update admin status", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) UpdateAdminPassword(ctx context.Context, tx *sql.Tx, id uuid.UUID, password string) error {
+	query := "UPDATE admin SET password = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, password, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update admin password", "error", err.Error())
+		return err
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update admin password", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) DeleteAdmin(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	query := "DELETE FROM admin WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete admin", "error", err.Error())
+		return err
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete admin", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) UpdateAdminTOTP(ctx context.Context, tx *sql.Tx, id uuid.UUID, secret string, enabled bool) error {
+	var totpSecret sql.NullString
+	if secret != "" {
+		totpSecret = sql.NullString{String: secret, Valid: true}
+	}
+
+	query := "UPDATE admin SET totp_secret = ?, totp_enabled = ?, totp_last_step = 0 WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, totpSecret, enabled, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update admin totp", "error", err.Error())
+		return err
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update admin totp", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateAdminTOTPStep only moves the step forward, so a code that was already
+// used (or an older one) cannot be replayed.
+func (repo *RepositoryImpl) UpdateAdminTOTPStep(ctx context.Context, tx *sql.Tx, id uuid.UUID, step int64) error {
+	query := "UPDATE admin SET totp_last_step = ? WHERE id = ? AND totp_last_step < ?"
+	result, err := tx.ExecContext(ctx, query, step, id, step)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update admin totp step", "error", err.Error())
+		return err
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update admin totp step", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, adminId uuid.UUID, codeHashes []string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM admin_recovery_codes WHERE admin_id = ?", adminId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("replace recovery codes", "error", err.Error())
+		return err
+	}
+
+	query := "INSERT INTO admin_recovery_codes(id, admin_id, code_hash) VALUES(?, ?, ?)"
+	for _, hash := range codeHashes {
+		_, err := tx.ExecContext(ctx, query, uuid.NewString(), adminId, hash)
+		if err != nil {
+			logger.GetLogger("repository-log").Log("replace recovery codes", "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) UseRecoveryCode(ctx context.Context, tx *sql.Tx, adminId uuid.UUID, codeHash string) error {
+	query := "UPDATE admin_recovery_codes SET used_at = ? WHERE admin_id = ? AND code_hash = ? AND used_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, time.Now(), adminId, codeHash)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("use recovery code", "error", err.Error())
+		return err
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("use recovery code", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) AddRefreshToken(ctx context.Context, tx *sql.Tx, entity *domain.RefreshToken) error {
+	query := "INSERT INTO refresh_tokens(id, family_id, admin_id, token_hash, expires_at) VALUES(?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.FamilyId, entity.AdminId, entity.TokenHash, entity.ExpiresAt)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add refresh token", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) GetRefreshToken(ctx context.Context, db *sql.DB, tokenHash string) (*domain.RefreshToken, error) {
+	query := "SELECT id, family_id, admin_id, token_hash, expires_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash = ?"
+	row := db.QueryRowContext(ctx, query, tokenHash)
+
+	var token domain.RefreshToken
+	err := row.Scan(&token.Id, &token.FamilyId, &token.AdminId, &token.TokenHash, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RevokeRefreshToken only revokes a token that is still active, so two
+// concurrent refreshes with the same token cannot both succeed.
+func (repo *RepositoryImpl) RevokeRefreshToken(ctx context.Context, tx *sql.Tx, id string) error {
+	query := "UPDATE refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("revoke refresh token", "error", err.Error())
+		return err
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("revoke refresh token", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) RevokeRefreshTokenFamily(ctx context.Context, tx *sql.Tx, familyId string) error {
+	query := "UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL"
+	_, err := tx.ExecContext(ctx, query, time.Now(), familyId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("revoke refresh token family", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) RevokeAdminRefreshTokens(ctx context.Context, tx *sql.Tx, adminId uuid.UUID) error {
+	query := "UPDATE refresh_tokens SET revoked_at = ? WHERE admin_id = ? AND revoked_at IS NULL"
+	_, err := tx.ExecContext(ctx, query, time.Now(), adminId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("revoke admin refresh tokens", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) AddRevokedAccessToken(ctx context.Context, tx *sql.Tx, jti string, expiresAt time.Time) error {
+	query := "INSERT IGNORE INTO revoked_access_tokens(jti, expires_at) VALUES(?, ?)"
+	_, err := tx.ExecContext(ctx, query, jti, expiresAt)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("revoke access token", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked also reports tokens of disabled or deleted admins as
+// revoked, since their access tokens are not tracked individually.
+func (repo *RepositoryImpl) IsAccessTokenRevoked(ctx context.Context, db *sql.DB, jti string, adminId uuid.UUID) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM revoked_access_tokens WHERE jti = ?) OR NOT EXISTS(SELECT 1 FROM admin WHERE id = ? AND disabled_at IS NULL)"
+	var revoked bool
+	err := db.QueryRowContext(ctx, query, jti, adminId).Scan(&revoked)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("check revoked access token", "error", err.Error())
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+// NextSequenceValue increments the named counter and returns the new value.
+// The UPDATE locks the counter row until tx ends, so concurrent callers never
+// see the same value.
+func (repo *RepositoryImpl) NextSequenceValue(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+	query := "UPDATE sequences SET value = LAST_INSERT_ID(value + 1) WHERE name = ?"
+	result, err := tx.ExecContext(ctx, query, name)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("next sequence value", "error", err.Error())
+		return 0, err
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("next sequence value", "error", err.Error())
+		return 0, err
+	}
+	if rowAff == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	value, err := result.LastInsertId()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("next sequence value", "error", err.Error())
+		return 0, err
+	}
+
+	return value, nil
+}
+
+func (repo *RepositoryImpl) AddProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain) (*domain.Domain, error) {
+	query := "INSERT INTO products(id, name, description, stock, price, category_id, created_at, created_by) VALUES(?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.Description, entity.Stock, entity.Price, entity.CategoryId, entity.CreatedAt, actorId(ctx))
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add product", "error", err.Error())
+		return nil, translateProductError(err)
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add product", "error", err.Error())
+		return nil, err
+	}
+	if rowAff == 0 {
+		return nil, errors.New("no rows inserted")
+	}
+	entity.Version = 1
+
+	return entity, nil
+}
+
+// productColumns is the column list scanProduct expects.
+const productColumns = "id, name, description, stock, price, category_id, created_at, modified_at, version, deleted_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProduct(row rowScanner) (*domain.Domain, error) {
+	var product domain.Domain
+	var description sql.NullString
+	var categoryId sql.NullInt64
+	var deletedAt sql.NullTime
+	err := row.Scan(&product.Id, &product.Name, &description, &product.Stock, &product.Price, &categoryId, &product.CreatedAt, &product.ModifiedAt, &product.Version, &deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	product.Description = description.String
+	if categoryId.Valid {
+		id := int(categoryId.Int64)
+		product.CategoryId = &id
+	}
+	if deletedAt.Valid {
+		product.DeletedAt = &deletedAt.Time
+	}
+	return &product, nil
+}
+
+// GetProduct also returns products in the trash, so orders keep showing the
+// product they were placed for; callers check DeletedAt where that matters.
+func (repo *RepositoryImpl) GetProduct(ctx context.Context, db *sql.DB, id string) (*domain.Domain, error) {
+	row := db.QueryRowContext(ctx, "SELECT "+productColumns+" FROM products WHERE id = ?", id)
+	product, err := scanProduct(row)
+	if err != nil {
+		return nil, translateError("product", err)
+	}
+
+	return product, nil
+}
+
+func (repo *RepositoryImpl) GetProducts(ctx context.Context, db *sql.DB, filter *domain.ProductFilter) ([]*domain.Domain, error) {
+	where, args := productConditions(filter)
+
+	column := productSortColumns[filter.SortBy]
+	direction, comparison := "ASC", ">"
+	if filter.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		value, err := helper.ProductCursorValue(filter.SortBy, filter.After.Value)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", column, comparison, column, comparison))
+		args = append(args, value, value, filter.After.Id)
+	}
+
+	query := "SELECT " + productColumns + " FROM products"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT ?", column, direction, direction)
+	args = append(args, filter.Limit)
+	if filter.After == nil && filter.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get product", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*domain.Domain
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			logger.GetLogger("repository-log").Log("get product", "error", err.Error())
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get product", "error", err.Error())
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (repo *RepositoryImpl) CountProducts(ctx context.Context, db *sql.DB, filter *domain.ProductFilter) (int, error) {
+	where, args := productConditions(filter)
+
+	query := "SELECT COUNT(*) FROM products"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		logger.GetLogger("repository-log").Log("count products", "error", err.Error())
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// productSortColumns whitelists the columns a product list may be sorted by.
+var productSortColumns = map[string]string{
+	"name":       "name",
+	"price":      "price",
+	"stock":      "stock",
+	"created_at": "created_at",
+}
+
+func productConditions(filter *domain.ProductFilter) ([]string, []interface{}) {
+	where := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if filter.Deleted {
+		where[0] = "deleted_at IS NOT NULL"
+	}
+
+	if filter.Name != "" {
+		where = append(where, "name LIKE ?")
+		args = append(args, "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.MinPrice != nil {
+		where = append(where, "price >= ?")
+		args = append(args, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		where = append(where, "price <= ?")
+		args = append(args, *filter.MaxPrice)
+	}
+	if filter.InStock {
+		where = append(where, "stock > 0")
+	}
+	if filter.CategoryId != nil {
+		if *filter.CategoryId == 0 {
+			where = append(where, "category_id IS NULL")
+		} else {
+			where = append(where, "category_id = ?")
+			args = append(args, *filter.CategoryId)
+		}
+	}
+
+	return where, args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// DeleteProduct moves a product to the trash. Orders keep referencing it and
+// PurgeDeletedProducts removes it for good later.
+func (repo *RepositoryImpl) DeleteProduct(ctx context.Context, tx *sql.Tx, id string, version int) error {
+	query := "UPDATE products SET deleted_at = ?, deleted_by = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, time.Now(), actorId(ctx), id, version)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete product", "error", err.Error())
+		return translateError("product", err)
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete product", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return missingOrStale(ctx, tx, productExistsQuery, "product", id)
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) UpdateProduct(ctx context.Context, tx *sql.Tx, patch *domain.ProductPatch, id string, version int) (*domain.Domain, error) {
+	var sets []string
+	var args []interface{}
+	if patch.Name != nil {
+		sets = append(sets, "name = ?")
+		args = append(args, *patch.Name)
+	}
+	if patch.Description != nil {
+		sets = append(sets, "description = ?")
+		args = append(args, *patch.Description)
+	}
+	if patch.Price != nil {
+		sets = append(sets, "price = ?")
+		args = append(args, *patch.Price)
+	}
+	if patch.CategoryId != nil {
+		sets = append(sets, "category_id = NULLIF(?, 0)")
+		args = append(args, *patch.CategoryId)
+	}
+	sets = append(sets, "modified_at = ?", "modified_by = ?", "version = version + 1")
+	args = append(args, patch.ModifiedAt, actorId(ctx), id, version)
+
+	query := "UPDATE products SET " + strings.Join(sets, ", ") + " WHERE id = ? AND version = ? AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update product", "error", err.Error())
+		return nil, translateProductError(err)
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update product", "error", err.Error())
+		return nil, err
+	}
+	if rowAff == 0 {
+		return nil, missingOrStale(ctx, tx, productExistsQuery, "product", id)
+	}
+
+	product, err := scanProduct(tx.QueryRowContext(ctx, "SELECT "+productColumns+" FROM products WHERE id = ?", id))
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update product", "error", err.Error())
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// MoveStock applies m.Delta to the product's stock and records m in the
+// ledger, filling in StockAfter. It fails with a stock conflict rather than
+// let stock go below zero. The check and the change are one UPDATE, which
+// keeps the product row locked until tx ends, so concurrent movements cannot
+// oversell. Stock can still be given back to a product in the trash. The
+// product's version is left alone, so stock changes never invalidate an
+// admin's ETag.
+func (repo *RepositoryImpl) MoveStock(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error {
+	query := "UPDATE products SET stock = stock + ? WHERE id = ? AND (? > 0 OR deleted_at IS NULL) AND stock + ? >= 0"
+	result, err := tx.ExecContext(ctx, query, m.Delta, m.ProductId, m.Delta, m.Delta)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("move stock", "error", err.Error())
+		return translateError("product", err)
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("move stock", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		var exists int
+		if err := tx.QueryRowContext(ctx, productExistsQuery, m.ProductId).Scan(&exists); err != nil {
+			return translateError("product", err)
+		}
+		return &domain.Error{Kind: domain.KindConflict, Entity: "stock", Message: "Not enough stock for this change."}
+	}
+
+	if err := tx.QueryRowContext(ctx, "SELECT stock FROM products WHERE id = ?", m.ProductId).Scan(&m.StockAfter); err != nil {
+		logger.GetLogger("repository-log").Log("move stock", "error", err.Error())
+		return err
+	}
+
+	return repo.AddStockMovement(ctx, tx, m)
+}
+
+// AddStockMovement records m without touching the product's stock, for
+// stock a product was created with.
+func (repo *RepositoryImpl) AddStockMovement(ctx context.Context, tx *sql.Tx, m *domain.StockMovement) error {
+	query := "INSERT INTO stock_movements (product_id, kind, delta, stock_after, reason, order_id, created_at, created_by) VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, m.ProductId, m.Kind, m.Delta, m.StockAfter, m.Reason, m.OrderId, m.CreatedAt, actorId(ctx))
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add stock movement", "error", err.Error())
+		return translateError("stock_movement", err)
+	}
+
+	m.Id, err = result.LastInsertId()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add stock movement", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) CountStockMovements(ctx context.Context, db *sql.DB, productId string) (int, error) {
+	var total int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM stock_movements WHERE product_id = ?", productId).Scan(&total)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("count stock movements", "error", err.Error())
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// GetStockMovements returns the product's movements newest first, with the
+// username of the admin behind each.
+func (repo *RepositoryImpl) GetStockMovements(ctx context.Context, db *sql.DB, filter *domain.StockMovementFilter) ([]*domain.StockMovement, error) {
+	query := "SELECT m.id, m.product_id, m.kind, m.delta, m.stock_after, m.reason, m.order_id, a.username, m.created_at " +
+		"FROM stock_movements m LEFT JOIN admin a ON a.id = m.created_by WHERE m.product_id = ?"
+	args := []interface{}{filter.ProductId}
+	if filter.After != nil {
+		after, err := strconv.ParseInt(filter.After.Id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		query += " AND m.id < ?"
+		args = append(args, after)
+	}
+	query += " ORDER BY m.id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get stock movements", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []*domain.StockMovement
+	for rows.Next() {
+		var m domain.StockMovement
+		var reason sql.NullString
+		if err := rows.Scan(&m.Id, &m.ProductId, &m.Kind, &m.Delta, &m.StockAfter, &reason, &m.OrderId, &m.Actor, &m.CreatedAt); err != nil {
+			logger.GetLogger("repository-log").Log("get stock movements", "error", err.Error())
+			return nil, err
+		}
+		m.Reason = reason.String
+		movements = append(movements, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get stock movements", "error", err.Error())
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (repo *RepositoryImpl) RestoreProduct(ctx context.Context, tx *sql.Tx, id string) (*domain.Domain, error) {
+	query := "UPDATE products SET deleted_at = NULL, deleted_by = NULL, modified_at = ?, modified_by = ?, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL"
+	result, err := tx.ExecContext(ctx, query, time.Now(), actorId(ctx), id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("restore product", "error", err.Error())
+		return nil, translateError("product", err)
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("restore product", "error", err.Error())
+		return nil, err
+	}
+	if rowAff == 0 {
+		return nil, translateError("product", sql.ErrNoRows)
+	}
+
+	product, err := scanProduct(tx.QueryRowContext(ctx, "SELECT "+productColumns+" FROM products WHERE id = ?", id))
+	if err != nil {
+		logger.GetLogger("repository-log").Log("restore product", "error", err.Error())
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// purgeableProducts matches products trashed before the cutoff argument.
+// Products that still have orders stay in the trash so the order history
+// keeps its product, as do products still bundled in a package.
+const purgeableProducts = "products.deleted_at < ? AND NOT EXISTS (SELECT 1 FROM orders WHERE orders.product_id = products.id) " +
+	"AND NOT EXISTS (SELECT 1 FROM package_items WHERE package_items.product_id = products.id)"
+
+// PurgeDeletedProducts permanently deletes products trashed before cutoff,
+// together with their image rows.
+func (repo *RepositoryImpl) PurgeDeletedProducts(ctx context.Context, db *sql.DB, cutoff time.Time) (int64, error) {
+	query := "DELETE FROM products WHERE " + purgeableProducts
+	result, err := db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("purge products", "error", err.Error())
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+const productImageColumns = "id, product_id, object_key, thumbnail_key, content_type, size, position, created_at"
+
+func scanProductImage(row rowScanner) (*domain.ProductImage, error) {
+	var image domain.ProductImage
+	err := row.Scan(&image.Id, &image.ProductId, &image.Key, &image.ThumbnailKey, &image.ContentType, &image.Size, &image.Position, &image.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// AddProductImage appends image after the product's existing images.
+func (repo *RepositoryImpl) AddProductImage(ctx context.Context, tx *sql.Tx, image *domain.ProductImage) error {
+	query := "INSERT INTO product_images (id, product_id, object_key, thumbnail_key, content_type, size, position, created_by) " +
+		"SELECT ?, ?, ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0), ? FROM product_images WHERE product_id = ?"
+	_, err := tx.ExecContext(ctx, query, image.Id, image.ProductId, image.Key, image.ThumbnailKey, image.ContentType, image.Size, actorId(ctx), image.ProductId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add product image", "error", err.Error())
+		return translateError("product", err)
+	}
+
+	return nil
+}
+
+// GetProductImages returns the images of all productIds in display order.
+func (repo *RepositoryImpl) GetProductImages(ctx context.Context, db *sql.DB, productIds []string) ([]*domain.ProductImage, error) {
+	if len(productIds) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(productIds)), ", ")
+	args := make([]interface{}, len(productIds))
+	for i, id := range productIds {
+		args[i] = id
+	}
+
+	query := "SELECT " + productImageColumns + " FROM product_images WHERE product_id IN (" + placeholders + ") ORDER BY product_id, position"
+	return repo.queryProductImages(ctx, db, "get product images", query, args...)
+}
+
+// GetPurgeableProductImages lists the images PurgeDeletedProducts would
+// remove for cutoff, so their files can be deleted too.
+func (repo *RepositoryImpl) GetPurgeableProductImages(ctx context.Context, db *sql.DB, cutoff time.Time) ([]*domain.ProductImage, error) {
+	query := "SELECT " + productImageColumns + " FROM product_images WHERE product_id IN (SELECT id FROM products WHERE " + purgeableProducts + ")"
+	return repo.queryProductImages(ctx, db, "get purgeable images", query, cutoff)
+}
+
+func (repo *RepositoryImpl) queryProductImages(ctx context.Context, db *sql.DB, action string, query string, args ...interface{}) ([]*domain.ProductImage, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log(action, "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []*domain.ProductImage
+	for rows.Next() {
+		image, err := scanProductImage(rows)
+		if err != nil {
+			logger.GetLogger("repository-log").Log(action, "error", err.Error())
+			return nil, err
+		}
+		images = append(images, image)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log(action, "error", err.Error())
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// DeleteProductImage removes the image row and returns it so the caller can
+// delete the stored files.
+func (repo *RepositoryImpl) DeleteProductImage(ctx context.Context, tx *sql.Tx, productId string, imageId string) (*domain.ProductImage, error) {
+	row := tx.QueryRowContext(ctx, "SELECT "+productImageColumns+" FROM product_images WHERE id = ? AND product_id = ? FOR UPDATE", imageId, productId)
+	image, err := scanProductImage(row)
+	if err != nil {
+		return nil, translateError("image", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM product_images WHERE id = ?", imageId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete product image", "error", err.Error())
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (repo *RepositoryImpl) GetProductVariants(ctx context.Context, db *sql.DB, productId string) ([]*domain.ProductVariant, error) {
+	query := "SELECT id, product_id, name, price_delta, stock, position FROM product_variants WHERE product_id = ? ORDER BY position, name"
+	rows, err := db.QueryContext(ctx, query, productId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get product variants", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []*domain.ProductVariant
+	for rows.Next() {
+		var variant domain.ProductVariant
+		if err := rows.Scan(&variant.Id, &variant.ProductId, &variant.Name, &variant.PriceDelta, &variant.Stock, &variant.Position); err != nil {
+			logger.GetLogger("repository-log").Log("get product variants", "error", err.Error())
+			return nil, err
+		}
+		variants = append(variants, &variant)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get product variants", "error", err.Error())
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// AddProductVariant only inserts when the product exists and is not in the
+// trash.
+func (repo *RepositoryImpl) AddProductVariant(ctx context.Context, tx *sql.Tx, entity *domain.ProductVariant) error {
+	query := "INSERT INTO product_variants (id, product_id, name, price_delta, stock, position) " +
+		"SELECT ?, id, ?, ?, ?, ? FROM products WHERE id = ? AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.PriceDelta, entity.Stock, entity.Position, entity.ProductId)
+	return checkWrite(result, err, "add product variant", "variant", "product")
+}
+
+func (repo *RepositoryImpl) UpdateProductVariant(ctx context.Context, tx *sql.Tx, entity *domain.ProductVariant) error {
+	query := "UPDATE product_variants SET name = ?, price_delta = ?, stock = ?, position = ? WHERE id = ? AND product_id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Name, entity.PriceDelta, entity.Stock, entity.Position, entity.Id, entity.ProductId)
+	return checkWrite(result, err, "update product variant", "variant", "variant")
+}
+
+// DeleteProductVariant keeps orders placed for the variant; they still carry
+// the variant name.
+func (repo *RepositoryImpl) DeleteProductVariant(ctx context.Context, tx *sql.Tx, productId string, id string) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM product_variants WHERE id = ? AND product_id = ?", id, productId)
+	return checkWrite(result, err, "delete product variant", "variant", "variant")
+}
+
+// GetOptionGroups returns the product's option groups with their options.
+func (repo *RepositoryImpl) GetOptionGroups(ctx context.Context, db *sql.DB, productId string) ([]*domain.OptionGroup, error) {
+	query := "SELECT id, product_id, name, min_select, max_select, position FROM product_option_groups WHERE product_id = ? ORDER BY position, name"
+	rows, err := db.QueryContext(ctx, query, productId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get option groups", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []*domain.OptionGroup
+	byId := make(map[string]*domain.OptionGroup)
+	for rows.Next() {
+		group := domain.OptionGroup{Options: []*domain.ProductOption{}}
+		if err := rows.Scan(&group.Id, &group.ProductId, &group.Name, &group.MinSelect, &group.MaxSelect, &group.Position); err != nil {
+			logger.GetLogger("repository-log").Log("get option groups", "error", err.Error())
+			return nil, err
+		}
+		groups = append(groups, &group)
+		byId[group.Id] = &group
+	}
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get option groups", "error", err.Error())
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	query = "SELECT o.id, o.group_id, o.name, o.price_delta, o.stock, o.position FROM product_options o " +
+		"JOIN product_option_groups g ON g.id = o.group_id WHERE g.product_id = ? ORDER BY o.position, o.name"
+	optionRows, err := db.QueryContext(ctx, query, productId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get option groups", "error", err.Error())
+		return nil, err
+	}
+	defer optionRows.Close()
+
+	for optionRows.Next() {
+		var option domain.ProductOption
+		if err := optionRows.Scan(&option.Id, &option.GroupId, &option.Name, &option.PriceDelta, &option.Stock, &option.Position); err != nil {
+			logger.GetLogger("repository-log").Log("get option groups", "error", err.Error())
+			return nil, err
+		}
+		if group, ok := byId[option.GroupId]; ok {
+			group.Options = append(group.Options, &option)
+		}
+	}
+	if err := optionRows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get option groups", "error", err.Error())
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (repo *RepositoryImpl) AddOptionGroup(ctx context.Context, tx *sql.Tx, entity *domain.OptionGroup) error {
+	query := "INSERT INTO product_option_groups (id, product_id, name, min_select, max_select, position) " +
+		"SELECT ?, id, ?, ?, ?, ? FROM products WHERE id = ? AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.MinSelect, entity.MaxSelect, entity.Position, entity.ProductId)
+	return checkWrite(result, err, "add option group", "option_group", "product")
+}
+
+func (repo *RepositoryImpl) UpdateOptionGroup(ctx context.Context, tx *sql.Tx, entity *domain.OptionGroup) error {
+	query := "UPDATE product_option_groups SET name = ?, min_select = ?, max_select = ?, position = ? WHERE id = ? AND product_id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Name, entity.MinSelect, entity.MaxSelect, entity.Position, entity.Id, entity.ProductId)
+	return checkWrite(result, err, "update option group", "option_group", "option_group")
+}
+
+// DeleteOptionGroup also deletes the group's options.
+func (repo *RepositoryImpl) DeleteOptionGroup(ctx context.Context, tx *sql.Tx, productId string, id string) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM product_option_groups WHERE id = ? AND product_id = ?", id, productId)
+	return checkWrite(result, err, "delete option group", "option_group", "option_group")
+}
+
+// AddProductOption only inserts when the group belongs to productId.
+func (repo *RepositoryImpl) AddProductOption(ctx context.Context, tx *sql.Tx, productId string, entity *domain.ProductOption) error {
+	query := "INSERT INTO product_options (id, group_id, name, price_delta, stock, position) " +
+		"SELECT ?, id, ?, ?, ?, ? FROM product_option_groups WHERE id = ? AND product_id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.PriceDelta, entity.Stock, entity.Position, entity.GroupId, productId)
+	return checkWrite(result, err, "add product option", "option", "option_group")
+}
+
+// optionOfProduct restricts option writes to options whose group belongs to
+// the product in the URL.
+const optionOfProduct = "id = ? AND group_id IN (SELECT id FROM product_option_groups WHERE id = ? AND product_id = ?)"
+
+func (repo *RepositoryImpl) UpdateProductOption(ctx context.Context, tx *sql.Tx, productId string, entity *domain.ProductOption) error {
+	query := "UPDATE product_options SET name = ?, price_delta = ?, stock = ?, position = ? WHERE " + optionOfProduct
+	result, err := tx.ExecContext(ctx, query, entity.Name, entity.PriceDelta, entity.Stock, entity.Position, entity.Id, entity.GroupId, productId)
+	return checkWrite(result, err, "update product option", "option", "option")
+}
+
+func (repo *RepositoryImpl) DeleteProductOption(ctx context.Context, tx *sql.Tx, productId string, groupId string, id string) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM product_options WHERE "+optionOfProduct, id, groupId, productId)
+	return checkWrite(result, err, "delete product option", "option", "option")
+}
+
+// checkWrite finishes a single-row write: driver errors are translated for
+// entity, and a write that matched no row reports missing as not found.
+func checkWrite(result sql.Result, err error, action string, entity string, missing string) error {
+	if err != nil {
+		logger.GetLogger("repository-log").Log(action, "error", err.Error())
+		return translateError(entity, err)
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log(action, "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		return translateError(missing, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// categoryColumns is the column list scanCategory expects, selected from
+// categories aliased as c. Only live products are counted.
+const categoryColumns = "c.id, c.name, c.description, c.position, c.created_at, c.modified_at, " +
+	"(SELECT COUNT(*) FROM products p WHERE p.category_id = c.id AND p.deleted_at IS NULL)"
+
+func scanCategory(row rowScanner) (*domain.Category, error) {
+	var category domain.Category
+	var description sql.NullString
+	err := row.Scan(&category.Id, &category.Name, &description, &category.Position, &category.CreatedAt, &category.ModifiedAt, &category.ProductCount)
+	if err != nil {
+		return nil, err
+	}
+	category.Description = description.String
+	return &category, nil
+}
+
+func (repo *RepositoryImpl) GetCategories(ctx context.Context, db *sql.DB) ([]*domain.Category, error) {
+	rows, err := db.QueryContext(ctx, "SELECT "+categoryColumns+" FROM categories c ORDER BY c.position, c.name")
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get categories", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			logger.GetLogger("repository-log").Log("get categories", "error", err.Error())
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get categories", "error", err.Error())
+		return nil, err
+	}
 
-func NewRepositoryImpl() Repository {
-	return &RepositoryImpl{}
+	return categories, nil
 }
 
-func (repo *RepositoryImpl) Login(ctx context.Context, db *sql.DB, entity *domain.Admin) (*domain.Admin, error) {
-	query := "SELECT id, username, password FROM admin WHERE username = ?"
-	row := db.QueryRowContext(ctx, query, entity.Username)
+func (repo *RepositoryImpl) GetCategory(ctx context.Context, db *sql.DB, id int) (*domain.Category, error) {
+	category, err := scanCategory(db.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM categories c WHERE c.id = ?", id))
+	if err != nil {
+		return nil, translateError("category", err)
+	}
 
-	var response domain.Admin
-	err := row.Scan(&response.Id, &response.Username, &response.Password)
+	return category, nil
+}
+
+func (repo *RepositoryImpl) AddCategory(ctx context.Context, tx *sql.Tx, entity *domain.Category) (*domain.Category, error) {
+	query := "INSERT INTO categories(name, description, position, created_at, created_by) VALUES(?, ?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, entity.Name, entity.Description, entity.Position, entity.CreatedAt, actorId(ctx))
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add category", "error", err.Error())
+		return nil, translateError("category", err)
+	}
+
+	id, err := result.LastInsertId()
 	if err != nil {
+		logger.GetLogger("repository-log").Log("add category", "error", err.Error())
 		return nil, err
 	}
+	entity.Id = int(id)
 
-	return &response, nil
+	return entity, nil
 }
 
-func (repo *RepositoryImpl) AddProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain) (*domain.Domain, error) {
-	query := "INSERT INTO products(id, name, description, stock, price, created_at) VALUES(?, ?, ?, ?, ?, ?)"
-	result, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.Description, entity.Stock, entity.Price, entity.CreatedAt)
+func (repo *RepositoryImpl) UpdateCategory(ctx context.Context, tx *sql.Tx, entity *domain.Category, id int) (*domain.Category, error) {
+	query := "UPDATE categories SET name = ?, description = ?, position = ?, modified_at = ?, modified_by = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Name, entity.Description, entity.Position, entity.ModifiedAt, actorId(ctx), id)
 	if err != nil {
-		logger.GetLogger("repository-log").Log("add product", "error", err.Error())
+		logger.GetLogger("repository-log").Log("update category", "error", err.Error())
+		return nil, translateError("category", err)
+	}
+
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update category", "error", err.Error())
 		return nil, err
 	}
+	if rowAff == 0 {
+		return nil, translateError("category", sql.ErrNoRows)
+	}
+
+	category, err := scanCategory(tx.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM categories c WHERE c.id = ?", id))
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update category", "error", err.Error())
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// DeleteCategory refuses to delete a category that still holds live
+// products. Products in the trash simply lose their category.
+func (repo *RepositoryImpl) DeleteCategory(ctx context.Context, tx *sql.Tx, id int) error {
+	query := "DELETE FROM categories WHERE id = ? AND NOT EXISTS (SELECT 1 FROM products WHERE category_id = ? AND deleted_at IS NULL)"
+	result, err := tx.ExecContext(ctx, query, id, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete category", "error", err.Error())
+		return translateError("category", err)
+	}
 
 	rowAff, err := result.RowsAffected()
-	if err != nil || rowAff == 0 {
-		logger.GetLogger("repository-log").Log("add product", "error", err.Error())
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete category", "error", err.Error())
+		return err
+	}
+	if rowAff == 0 {
+		var exists int
+		if err := tx.QueryRowContext(ctx, "SELECT 1 FROM categories WHERE id = ?", id).Scan(&exists); err != nil {
+			return translateError("category", err)
+		}
+		return domain.NewError(domain.KindForeignKeyInUse, "category", nil)
+	}
+
+	return nil
+}
+
+const packageColumns = "id, name, description, pricing, price, discount, created_at, modified_at"
+
+func scanPackage(row rowScanner) (*domain.Package, error) {
+	pkg := domain.Package{Items: []*domain.PackageItem{}}
+	var description sql.NullString
+	err := row.Scan(&pkg.Id, &pkg.Name, &description, &pkg.Pricing, &pkg.Price, &pkg.Discount, &pkg.CreatedAt, &pkg.ModifiedAt)
+	if err != nil {
 		return nil, err
 	}
+	pkg.Description = description.String
+	return &pkg, nil
+}
 
-	return entity, nil
+// packageItemColumns is the column list scanPackageItem expects, selected
+// from package_items aliased as i joined to products aliased as p.
+const packageItemColumns = "i.package_id, i.product_id, p.name, i.quantity, p.price, p.stock, p.deleted_at IS NOT NULL"
+
+func scanPackageItem(row rowScanner) (*domain.PackageItem, error) {
+	var item domain.PackageItem
+	err := row.Scan(&item.PackageId, &item.ProductId, &item.ProductName, &item.Quantity, &item.UnitPrice, &item.Stock, &item.Deleted)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
 }
 
-func (repo *RepositoryImpl) GetProducts(ctx context.Context, db *sql.DB) ([]*domain.Domain, error) {
-	query := "SELECT id, name, description, stock, price, created_at, modified_at FROM products"
-	rows, err := db.QueryContext(ctx, query)
+func (repo *RepositoryImpl) GetPackages(ctx context.Context, db *sql.DB) ([]*domain.Package, error) {
+	rows, err := db.QueryContext(ctx, "SELECT "+packageColumns+" FROM packages ORDER BY name")
 	if err != nil {
-		logger.GetLogger("repository-log").Log("get product", "error", err.Error())
+		logger.GetLogger("repository-log").Log("get packages", "error", err.Error())
 		return nil, err
 	}
 	defer rows.Close()
 
-	var products []*domain.Domain
+	var packages []*domain.Package
+	byId := make(map[string]*domain.Package)
 	for rows.Next() {
-		var product domain.Domain
-		var description sql.NullString
-
-		err := rows.Scan(&product.Id, &product.Name, &description, &product.Stock, &product.Price, &product.CreatedAt, &product.ModifiedAt)
+		pkg, err := scanPackage(rows)
 		if err != nil {
-			logger.GetLogger("repository-log").Log("get product", "error", err.Error())
+			logger.GetLogger("repository-log").Log("get packages", "error", err.Error())
 			return nil, err
 		}
+		packages = append(packages, pkg)
+		byId[pkg.Id] = pkg
+	}
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get packages", "error", err.Error())
+		return nil, err
+	}
+	if len(packages) == 0 {
+		return packages, nil
+	}
 
-		if description.Valid {
-			product.Description = description.String
+	items, err := getPackageItems(ctx, db, "")
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get packages", "error", err.Error())
+		return nil, err
+	}
+	for _, item := range items {
+		if pkg, ok := byId[item.PackageId]; ok {
+			pkg.Items = append(pkg.Items, item)
 		}
+	}
 
-		products = append(products, &product)
+	return packages, nil
+}
+
+func (repo *RepositoryImpl) GetPackage(ctx context.Context, db *sql.DB, id string) (*domain.Package, error) {
+	pkg, err := scanPackage(db.QueryRowContext(ctx, "SELECT "+packageColumns+" FROM packages WHERE id = ?", id))
+	if err != nil {
+		return nil, translateError("package", err)
 	}
 
-	return products, nil
+	items, err := getPackageItems(ctx, db, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get package", "error", err.Error())
+		return nil, err
+	}
+	pkg.Items = append(pkg.Items, items...)
+
+	return pkg, nil
 }
 
-func (repo *RepositoryImpl) DeleteProduct(ctx context.Context, tx *sql.Tx, id string) error {
-	query := "DELETE FROM products WHERE id = ?"
-	result, err := tx.ExecContext(ctx, query, id)
+// getPackageItems returns the items of packageId, or of every package when
+// it is empty.
+func getPackageItems(ctx context.Context, db *sql.DB, packageId string) ([]*domain.PackageItem, error) {
+	query := "SELECT " + packageItemColumns + " FROM package_items i JOIN products p ON p.id = i.product_id"
+	var args []interface{}
+	if packageId != "" {
+		query += " WHERE i.package_id = ?"
+		args = append(args, packageId)
+	}
+	query += " ORDER BY p.name"
+
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		logger.GetLogger("repository-log").Log("delete product", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*domain.PackageItem
+	for rows.Next() {
+		item, err := scanPackageItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (repo *RepositoryImpl) AddPackage(ctx context.Context, tx *sql.Tx, entity *domain.Package) error {
+	query := "INSERT INTO packages (id, name, description, pricing, price, discount, created_at, modified_at, created_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.Description, entity.Pricing, entity.Price, entity.Discount, entity.CreatedAt, entity.ModifiedAt, actorId(ctx))
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add package", "error", err.Error())
+		return translateError("package", err)
+	}
+
+	return addPackageItems(ctx, tx, entity)
+}
+
+// UpdatePackage replaces the package's fields and its whole item list.
+func (repo *RepositoryImpl) UpdatePackage(ctx context.Context, tx *sql.Tx, entity *domain.Package) error {
+	query := "UPDATE packages SET name = ?, description = ?, pricing = ?, price = ?, discount = ?, modified_at = ?, modified_by = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Name, entity.Description, entity.Pricing, entity.Price, entity.Discount, entity.ModifiedAt, actorId(ctx), entity.Id)
+	if err := checkWrite(result, err, "update package", "package", "package"); err != nil {
 		return err
 	}
 
-	rowAff, err := result.RowsAffected()
-	if err != nil || rowAff == 0 {
-		logger.GetLogger("repository-log").Log("delete product", "error", err.Error())
+	if _, err := tx.ExecContext(ctx, "DELETE FROM package_items WHERE package_id = ?", entity.Id); err != nil {
+		logger.GetLogger("repository-log").Log("update package", "error", err.Error())
 		return err
 	}
 
+	return addPackageItems(ctx, tx, entity)
+}
+
+// addPackageItems inserts the items of entity. Products that are missing or
+// in the trash cannot be added to a package.
+func addPackageItems(ctx context.Context, tx *sql.Tx, entity *domain.Package) error {
+	query := "INSERT INTO package_items (package_id, product_id, quantity) SELECT ?, id, ? FROM products WHERE id = ? AND deleted_at IS NULL"
+	for _, item := range entity.Items {
+		result, err := tx.ExecContext(ctx, query, entity.Id, item.Quantity, item.ProductId)
+		if err := checkWrite(result, err, "add package item", "package", "product"); err != nil {
+			if domain.IsKind(err, domain.KindNotFound) {
+				return &domain.Error{Kind: domain.KindValidation, Entity: "product", Message: fmt.Sprintf("Product %s does not exist.", item.ProductId), Err: err}
+			}
+			return err
+		}
+		item.PackageId = entity.Id
+	}
+
 	return nil
 }
 
-func (repo *RepositoryImpl) UpdateProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain, id string) (*domain.Domain, error) {
-	query := "UPDATE products SET name = ?, description = ?, stock = ?, price = ?, modified_at = ? WHERE id = ?"
-	result, err := tx.ExecContext(ctx, query, entity.Name, entity.Description, entity.Stock, entity.Price, entity.ModifiedAt, id)
+// DeletePackage refuses to delete a package that has been ordered.
+func (repo *RepositoryImpl) DeletePackage(ctx context.Context, tx *sql.Tx, id string) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM packages WHERE id = ?", id)
+	return checkWrite(result, err, "delete package", "package", "package")
+}
+
+// GetOrderOptions returns the options chosen on all orderIds.
+func (repo *RepositoryImpl) GetOrderOptions(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.OrderOption, error) {
+	if len(orderIds) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(orderIds)), ", ")
+	args := make([]interface{}, len(orderIds))
+	for i, id := range orderIds {
+		args[i] = id
+	}
+
+	query := "SELECT order_id, option_id, group_name, option_name, price_delta FROM order_options WHERE order_id IN (" + placeholders + ") ORDER BY order_id, group_name, option_name"
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		logger.GetLogger("repository-log").Log("update product", "error", err.Error())
+		logger.GetLogger("repository-log").Log("get order options", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []*domain.OrderOption
+	for rows.Next() {
+		var option domain.OrderOption
+		if err := rows.Scan(&option.OrderId, &option.OptionId, &option.GroupName, &option.Name, &option.PriceDelta); err != nil {
+			logger.GetLogger("repository-log").Log("get order options", "error", err.Error())
+			return nil, err
+		}
+		options = append(options, &option)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get order options", "error", err.Error())
 		return nil, err
 	}
 
-	rowAff, err := result.RowsAffected()
+	return options, nil
+}
+
+// orderColumns is the column list scanOrder expects.
+const orderColumns = "id, product_id, package_id, product_name, variant_id, variant_name, username, quantity, total, status, stock_reserved, created_at, modified_at, version"
+
+func scanOrder(row rowScanner) (*domain.Orders, error) {
+	var order domain.Orders
+	var productId sql.NullString
+	err := row.Scan(&order.Id, &productId, &order.PackageId, &order.ProductName, &order.VariantId, &order.VariantName, &order.Username, &order.Quantity, &order.Total, &order.Status, &order.StockReserved, &order.CreatedAt, &order.ModifiedAt, &order.Version)
 	if err != nil {
-		logger.GetLogger("repository-log").Log("update product", "error", err.Error())
 		return nil, err
 	}
-	if rowAff == 0 {
-		return nil, errors.New("no rows updated")
+	order.ProductId = productId.String
+	return &order, nil
+}
+
+func (repo *RepositoryImpl) GetOrder(ctx context.Context, db *sql.DB, id string) (*domain.Orders, error) {
+	order, err := scanOrder(db.QueryRowContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE id = ?", id))
+	if err != nil {
+		return nil, translateError("order", err)
 	}
 
-	var product domain.Domain
-	row := tx.QueryRowContext(ctx, "SELECT id, name, description, stock, price, created_at, modified_at FROM products WHERE id = ?", id)
-	err = row.Scan(&product.Id, &product.Name, &product.Description, &product.Stock, &product.Price, &product.CreatedAt, &product.ModifiedAt)
+	return order, nil
+}
+
+// GetOrderForUpdate reads the order and locks it until tx ends, so two
+// status changes to the same order are applied one after the other.
+func (repo *RepositoryImpl) GetOrderForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.Orders, error) {
+	order, err := scanOrder(tx.QueryRowContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE id = ? FOR UPDATE", id))
 	if err != nil {
-		logger.GetLogger("repository-log").Log("update product", "error", err.Error())
+		return nil, translateError("order", err)
+	}
+
+	return order, nil
+}
+
+// GetOrderStockLines lists the products an order takes stock from, with the
+// quantity of each: the ordered product, or every item of the ordered
+// package. Lines are sorted by product id so concurrent reservations lock
+// products in the same order.
+func (repo *RepositoryImpl) GetOrderStockLines(ctx context.Context, tx *sql.Tx, orderId string) ([]*domain.StockLine, error) {
+	query := "SELECT product_id, product_name, quantity FROM orders WHERE id = ? AND product_id IS NOT NULL " +
+		"UNION ALL SELECT i.product_id, p.name, i.quantity * o.quantity FROM orders o " +
+		"JOIN package_items i ON i.package_id = o.package_id JOIN products p ON p.id = i.product_id WHERE o.id = ? " +
+		"ORDER BY product_id"
+	rows, err := tx.QueryContext(ctx, query, orderId, orderId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get order stock lines", "error", err.Error())
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &product, nil
+	var lines []*domain.StockLine
+	for rows.Next() {
+		var line domain.StockLine
+		if err := rows.Scan(&line.ProductId, &line.ProductName, &line.Quantity); err != nil {
+			logger.GetLogger("repository-log").Log("get order stock lines", "error", err.Error())
+			return nil, err
+		}
+		lines = append(lines, &line)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get order stock lines", "error", err.Error())
+		return nil, err
+	}
+
+	return lines, nil
 }
 
-func (repo *RepositoryImpl) GetOrders(ctx context.Context, db *sql.DB) ([]*domain.Orders, error) {
-	// db.QueryRowContext(ctx, "SELECT SLEEP(9)")
-	query := "SELECT * FROM orders"
-	rows, err := db.QueryContext(ctx, query)
+// GetOrderReservedStock returns the stock the order still holds according to
+// its own reservation and restock movements, whatever its package holds now.
+func (repo *RepositoryImpl) GetOrderReservedStock(ctx context.Context, tx *sql.Tx, orderId string) ([]*domain.StockLine, error) {
+	query := "SELECT m.product_id, p.name, -SUM(m.delta) FROM stock_movements m JOIN products p ON p.id = m.product_id " +
+		"WHERE m.order_id = ? AND m.kind IN ('reservation', 'restock') GROUP BY m.product_id, p.name HAVING SUM(m.delta) < 0 " +
+		"ORDER BY m.product_id"
+	rows, err := tx.QueryContext(ctx, query, orderId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get order reserved stock", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []*domain.StockLine
+	for rows.Next() {
+		var line domain.StockLine
+		if err := rows.Scan(&line.ProductId, &line.ProductName, &line.Quantity); err != nil {
+			logger.GetLogger("repository-log").Log("get order reserved stock", "error", err.Error())
+			return nil, err
+		}
+		lines = append(lines, &line)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get order reserved stock", "error", err.Error())
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// ReserveOrderVariantStock takes the order's quantity from its variant and
+// options where they keep their own stock. It returns the name of the first
+// one that is short, leaving the caller to roll back.
+func (repo *RepositoryImpl) ReserveOrderVariantStock(ctx context.Context, tx *sql.Tx, orderId string) (string, error) {
+	queries := []string{
+		"UPDATE product_variants v JOIN orders o ON o.variant_id = v.id " +
+			"SET v.stock = v.stock - o.quantity, o.variant_reserved = o.quantity WHERE o.id = ? AND v.stock >= o.quantity",
+		"UPDATE product_options p JOIN order_options oo ON oo.option_id = p.id JOIN orders o ON o.id = oo.order_id " +
+			"SET p.stock = p.stock - o.quantity, oo.stock_reserved = o.quantity WHERE o.id = ? AND p.stock >= o.quantity",
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, orderId); err != nil {
+			logger.GetLogger("repository-log").Log("reserve order variant stock", "error", err.Error())
+			return "", err
+		}
+	}
+
+	query := "SELECT v.name FROM orders o JOIN product_variants v ON v.id = o.variant_id " +
+		"WHERE o.id = ? AND v.stock IS NOT NULL AND o.variant_reserved = 0 " +
+		"UNION ALL SELECT p.name FROM order_options oo JOIN product_options p ON p.id = oo.option_id " +
+		"WHERE oo.order_id = ? AND p.stock IS NOT NULL AND oo.stock_reserved = 0 LIMIT 1"
+	var short string
+	err := tx.QueryRowContext(ctx, query, orderId, orderId).Scan(&short)
+	if err != nil && err != sql.ErrNoRows {
+		logger.GetLogger("repository-log").Log("reserve order variant stock", "error", err.Error())
+		return "", err
+	}
+
+	return short, nil
+}
+
+// ReleaseOrderVariantStock gives back what ReserveOrderVariantStock took. A
+// variant or option removed since then has nothing to give back to.
+func (repo *RepositoryImpl) ReleaseOrderVariantStock(ctx context.Context, tx *sql.Tx, orderId string) error {
+	queries := []string{
+		"UPDATE product_variants v JOIN orders o ON o.variant_id = v.id SET v.stock = v.stock + o.variant_reserved WHERE o.id = ? AND o.variant_reserved > 0",
+		"UPDATE product_options p JOIN order_options oo ON oo.option_id = p.id SET p.stock = p.stock + oo.stock_reserved WHERE oo.order_id = ? AND oo.stock_reserved > 0",
+		"UPDATE orders SET variant_reserved = 0 WHERE id = ?",
+		"UPDATE order_options SET stock_reserved = 0 WHERE order_id = ?",
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, orderId); err != nil {
+			logger.GetLogger("repository-log").Log("release order variant stock", "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *RepositoryImpl) GetOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) ([]*domain.Orders, error) {
+	where, args := orderConditions(filter)
+
+	if filter.After != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, filter.After.Value)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, createdAt, createdAt, filter.After.Id)
+	}
+
+	query := "SELECT " + orderColumns + " FROM orders"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		logger.GetLogger("repository-log").Log("get orders", "error", err.Error())
 		return nil, err
@@ -131,13 +1514,12 @@ func (repo *RepositoryImpl) GetOrders(ctx context.Context, db *sql.DB) ([]*domai
 
 	var orders []*domain.Orders
 	for rows.Next() {
-		var order domain.Orders
-		err := rows.Scan(&order.Id, &order.ProductId, &order.ProductName, &order.Username, &order.Quantity, &order.Total, &order.Status, &order.CreatedAt, &order.ModifiedAt)
+		order, err := scanOrder(rows)
 		if err != nil {
 			logger.GetLogger("repository-log").Log("get orders", "error", err.Error())
 			return nil, err
 		}
-		orders = append(orders, &order)
+		orders = append(orders, order)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -148,36 +1530,181 @@ func (repo *RepositoryImpl) GetOrders(ctx context.Context, db *sql.DB) ([]*domai
 	return orders, nil
 }
 
-func (repo *RepositoryImpl) UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id string) error {
-	query := "UPDATE orders SET status = ? WHERE id = ?"
-	result, err := tx.ExecContext(ctx, query, entity.Status, id)
+func (repo *RepositoryImpl) CountOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) (int, error) {
+	where, args := orderConditions(filter)
+
+	query := "SELECT COUNT(*) FROM orders"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		logger.GetLogger("repository-log").Log("count orders", "error", err.Error())
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func orderConditions(filter *domain.OrderFilter) ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Username != "" {
+		where = append(where, "username = ?")
+		args = append(args, filter.Username)
+	}
+	if filter.ProductId != "" {
+		where = append(where, "product_id = ?")
+		args = append(args, filter.ProductId)
+	}
+	if filter.CreatedFrom != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *filter.CreatedFrom)
+	}
+	if filter.CreatedBefore != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *filter.CreatedBefore)
+	}
+	if filter.MinTotal != nil {
+		where = append(where, "total >= ?")
+		args = append(args, *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		where = append(where, "total <= ?")
+		args = append(args, *filter.MaxTotal)
+	}
+
+	return where, args
+}
+
+func (repo *RepositoryImpl) UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id string, version int) error {
+	query := "UPDATE orders SET status = ?, stock_reserved = ?, modified_by = ?, version = version + 1 WHERE id = ? AND version = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Status, entity.StockReserved, actorId(ctx), id, version)
 	if err != nil {
 		logger.GetLogger("repository-log").Log("update orders", "error", err.Error())
-		return err
+		return translateError("order", err)
 	}
 
 	rowAff, err := result.RowsAffected()
-	if err != nil || rowAff == 0 {
+	if err != nil {
 		logger.GetLogger("repository-log").Log("update orders", "error", err.Error())
 		return err
 	}
+	if rowAff == 0 {
+		return missingOrStale(ctx, tx, orderExistsQuery, "order", id)
+	}
 
 	return nil
 }
 
-func (repo *RepositoryImpl) DeleteOrder(ctx context.Context, tx *sql.Tx, id string) error {
-	query := "DELETE FROM orders WHERE id = ?"
-	result, err := tx.ExecContext(ctx, query, id)
+// AddOrderStatusChange records a status change in the order's history. It is
+// written with the update it describes, so it runs on the same transaction.
+func (repo *RepositoryImpl) AddOrderStatusChange(ctx context.Context, tx *sql.Tx, change *domain.OrderStatusChange) error {
+	query := "INSERT INTO order_status_history (order_id, from_status, to_status, username, note, created_at) VALUES (?, ?, ?, ?, NULLIF(?, ''), ?)"
+	result, err := tx.ExecContext(ctx, query, change.OrderId, change.FromStatus, change.ToStatus, actorUsername(ctx), change.Note, change.CreatedAt)
 	if err != nil {
-		logger.GetLogger("repository-log").Log("delete order", "error", err.Error())
+		logger.GetLogger("repository-log").Log("add order status change", "error", err.Error())
+		return translateError("order_status_change", err)
+	}
+
+	change.Id, err = result.LastInsertId()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add order status change", "error", err.Error())
 		return err
 	}
 
+	return nil
+}
+
+// GetOrderStatusHistory returns the order's status changes oldest first.
+func (repo *RepositoryImpl) GetOrderStatusHistory(ctx context.Context, db *sql.DB, orderId string) ([]*domain.OrderStatusChange, error) {
+	query := "SELECT id, order_id, from_status, to_status, username, note, created_at FROM order_status_history WHERE order_id = ? ORDER BY id"
+	rows, err := db.QueryContext(ctx, query, orderId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get order status history", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*domain.OrderStatusChange
+	for rows.Next() {
+		var change domain.OrderStatusChange
+		var note sql.NullString
+		if err := rows.Scan(&change.Id, &change.OrderId, &change.FromStatus, &change.ToStatus, &change.Username, &note, &change.CreatedAt); err != nil {
+			logger.GetLogger("repository-log").Log("get order status history", "error", err.Error())
+			return nil, err
+		}
+		change.Note = note.String
+		history = append(history, &change)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get order status history", "error", err.Error())
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (repo *RepositoryImpl) DeleteOrder(ctx context.Context, tx *sql.Tx, id string, version int) error {
+	query := "DELETE FROM orders WHERE id = ? AND version = ?"
+	result, err := tx.ExecContext(ctx, query, id, version)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete order", "error", err.Error())
+		return translateError("order", err)
+	}
+
 	rowAff, err := result.RowsAffected()
-	if err != nil || rowAff == 0 {
-		logger.GetLogger("repository-log").Log("update orders", "errors", err.Error())
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete order", "error", err.Error())
 		return err
 	}
+	if rowAff == 0 {
+		return missingOrStale(ctx, tx, orderExistsQuery, "order", id)
+	}
 
 	return nil
 }
+
+const (
+	productExistsQuery = "SELECT 1 FROM products WHERE id = ? AND deleted_at IS NULL"
+	orderExistsQuery   = "SELECT 1 FROM orders WHERE id = ?"
+)
+
+// missingOrStale explains why a versioned write matched no row: either the
+// row is gone or the client's version is out of date. existsQuery selects the
+// row by id.
+func missingOrStale(ctx context.Context, tx *sql.Tx, existsQuery string, entity string, id string) error {
+	var exists int
+	err := tx.QueryRowContext(ctx, existsQuery, id).Scan(&exists)
+	if err != nil {
+		return translateError(entity, err)
+	}
+	return domain.NewError(domain.KindStale, entity, nil)
+}
+
+// actorId is the admin making the request, recorded on rows it writes. It is
+// NULL for writes made outside an authenticated request.
+func actorId(ctx context.Context) sql.NullString {
+	claims, ok := helper.ClaimsFromContext(ctx)
+	if !ok {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: claims.Subject, Valid: true}
+}
+
+// actorUsername is the username in the requesting admin's token, or NULL
+// outside an authenticated request.
+func actorUsername(ctx context.Context) sql.NullString {
+	claims, ok := helper.ClaimsFromContext(ctx)
+	if !ok {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: claims.Username, Valid: true}
+}
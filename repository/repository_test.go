@@ -2,17 +2,23 @@ package repository
 
 import (
 	"catering-admin-go/domain"
+	"catering-admin-go/helper"
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetProducts(t *testing.T) {
 	now := time.Now()
+	categoryId := 2
 	id := "123e4567-e89b-12d3-a456-426614174000"
 
 	tests := []struct {
@@ -25,15 +31,18 @@ func TestGetProducts(t *testing.T) {
 			name: "Test GetProducts Success",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := mock.NewRows([]string{
-					"Id", "Name", "Description", "Stock", "Price", "CreatedAt", "ModifiedAt",
+					"Id", "Name", "Description", "Stock", "Price", "CategoryId", "CreatedAt", "ModifiedAt", "Version", "DeletedAt",
 				}).AddRow(
 					id,
 					"Product 1",
 					"1st Product",
 					10,
 					1000,
+					2,
 					now,
 					now,
+					1,
+					nil,
 				)
 
 				mock.ExpectQuery("(?i)select .* from products").WillReturnRows(rows)
@@ -46,6 +55,7 @@ func TestGetProducts(t *testing.T) {
 					Description: "1st Product",
 					Stock:       10,
 					Price:       1000,
+					CategoryId:  &categoryId,
 					CreatedAt:   &now,
 					ModifiedAt:  &now,
 				},
@@ -83,7 +93,7 @@ func TestGetProducts(t *testing.T) {
 			name: "empty result",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := mock.NewRows([]string{
-					"Id", "Name", "Description", "Stock", "Price", "CreatedAt", "ModifiedAt",
+					"Id", "Name", "Description", "Stock", "Price", "CategoryId", "CreatedAt", "ModifiedAt", "Version", "DeletedAt",
 				})
 				mock.ExpectQuery("(?i)select .* from products").WillReturnRows(rows)
 			},
@@ -94,15 +104,18 @@ func TestGetProducts(t *testing.T) {
 			name: "scan error due to type mismatch",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := mock.NewRows([]string{
-					"Id", "Name", "Description", "Stock", "Price", "CreatedAt", "ModifiedAt",
+					"Id", "Name", "Description", "Stock", "Price", "CategoryId", "CreatedAt", "ModifiedAt", "Version", "DeletedAt",
 				}).AddRow(
 					"wrong-type", // should be UUID
 					123,          // should be string
 					"desc",
 					"invalid-int",
 					"invalid-float",
+					nil,
 					time.Now(),
 					time.Now(),
+					1,
+					nil,
 				)
 				mock.ExpectQuery("(?i)select .* from products").WillReturnRows(rows)
 			},
@@ -122,7 +135,7 @@ func TestGetProducts(t *testing.T) {
 
 			repo := NewRepositoryImpl()
 
-			result, err := repo.GetProducts(context.Background(), db)
+			result, err := repo.GetProducts(context.Background(), db, &domain.ProductFilter{SortBy: "name", Limit: 20})
 
 			if tt.expectedErr {
 				if tt.name == "1 column missing" {
@@ -145,6 +158,144 @@ func TestGetProducts(t *testing.T) {
 
 }
 
+func TestGetProductsFilters(t *testing.T) {
+	minPrice, maxPrice := 1000, 5000
+	category, uncategorized := 3, 0
+	now := time.Now()
+	columns := []string{"id", "name", "description", "stock", "price", "category_id", "created_at", "modified_at", "version", "deleted_at"}
+
+	tests := []struct {
+		name      string
+		filter    *domain.ProductFilter
+		setupMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:   "Name, price range and stock with offset",
+			filter: &domain.ProductFilter{Name: "nasi_", MinPrice: &minPrice, MaxPrice: &maxPrice, InStock: true, SortBy: "price", Limit: 10, Offset: 20},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)^select .* from products where deleted_at is null and name like \? and price >= \? and price <= \? and stock > 0 order by price asc, id asc limit \? offset \?$`).
+					WithArgs(`%nasi\_%`, minPrice, maxPrice, 10, 20).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("PRD001", "Nasi Box", "desc", 5, 2000, 1, now, now, 1, nil))
+			},
+		},
+		{
+			name:   "Trash",
+			filter: &domain.ProductFilter{Deleted: true, SortBy: "name", Limit: 20},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)^select .* from products where deleted_at is not null order by name asc, id asc limit \?$`).
+					WithArgs(20).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("PRD002", "Nasi Kotak", nil, 0, 1500, nil, now, now, 2, now))
+			},
+		},
+		{
+			name:   "Category",
+			filter: &domain.ProductFilter{CategoryId: &category, SortBy: "name", Limit: 20},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)^select .* from products where deleted_at is null and category_id = \? order by name asc, id asc limit \?$`).
+					WithArgs(category, 20).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("PRD003", "Es Teh", nil, 40, 500, category, now, now, 1, nil))
+			},
+		},
+		{
+			name:   "Uncategorized",
+			filter: &domain.ProductFilter{CategoryId: &uncategorized, SortBy: "name", Limit: 20},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)^select .* from products where deleted_at is null and category_id is null order by name asc, id asc limit \?$`).
+					WithArgs(20).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name:   "Descending cursor",
+			filter: &domain.ProductFilter{SortBy: "stock", Desc: true, Limit: 5, Offset: 40, After: &domain.Cursor{Value: "12", Id: "PRD009"}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)^select .* from products where deleted_at is null and \(stock < \? or \(stock = \? and id < \?\)\) order by stock desc, id desc limit \?$`).
+					WithArgs(12, 12, "PRD009", 5).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			_, err = NewRepositoryImpl().GetProducts(context.Background(), db, tt.filter)
+			assert.NoError(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCountProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`(?i)^select count\(\*\) from products where deleted_at is null and stock > 0$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	total, err := NewRepositoryImpl().CountProducts(context.Background(), db, &domain.ProductFilter{InStock: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, total)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNextSequenceValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(mock sqlmock.Sqlmock)
+		expectedErr   error
+		expectedValue int64
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)^update sequences set value = last_insert_id\(value \+ 1\) where name = \?$`).
+					WithArgs("products").
+					WillReturnResult(sqlmock.NewResult(42, 1))
+			},
+			expectedValue: 42,
+		},
+		{
+			name: "Unknown sequence",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)^update sequences`).
+					WithArgs("products").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			value, err := NewRepositoryImpl().NextSequenceValue(context.Background(), tx, "products")
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedValue, value)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestAddProduct(t *testing.T) {
 	created_at := time.Now()
 	id := "123e4567-e89b-12d3-a456-426614174000"
@@ -163,8 +314,8 @@ func TestAddProduct(t *testing.T) {
 			name: "Success",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("(?i)insert\\s+into\\s+products\\s*\\(\\s*id\\s*,\\s*name\\s*,\\s*description\\s*,\\s*stock\\s*,\\s*price\\s*,\\s*created_at\\s*\\)\\s*values\\s*\\(\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*\\)").
-					WithArgs(id, name, description, stock, price, created_at).
+				mock.ExpectExec("(?i)insert\\s+into\\s+products\\s*\\(\\s*id\\s*,\\s*name\\s*,\\s*description\\s*,\\s*stock\\s*,\\s*price\\s*,\\s*category_id\\s*,\\s*created_at\\s*,\\s*created_by\\s*\\)\\s*values\\s*\\(\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*\\)").
+					WithArgs(id, name, description, stock, price, nil, created_at, nil).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			expectedErr: false,
@@ -181,8 +332,8 @@ func TestAddProduct(t *testing.T) {
 			name: "1 column missing except description",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("(?i)insert\\s+into\\s+products\\s*\\(\\s*id\\s*,\\s*name\\s*,\\s*description\\s*,\\s*stock\\s*,\\s*price\\s*,\\s*created_at\\s*\\)\\s*values\\s*\\(\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*\\)").
-					WithArgs(id, "", description, stock, price, created_at).
+				mock.ExpectExec("(?i)insert\\s+into\\s+products\\s*\\(\\s*id\\s*,\\s*name\\s*,\\s*description\\s*,\\s*stock\\s*,\\s*price\\s*,\\s*category_id\\s*,\\s*created_at\\s*,\\s*created_by\\s*\\)\\s*values\\s*\\(\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*\\)").
+					WithArgs(id, "", description, stock, price, nil, created_at, nil).
 					WillReturnError(errors.New("field name cannot empty"))
 			},
 			expectedErr: true,
@@ -260,29 +411,28 @@ func TestUpdateProduct(t *testing.T) {
 	tests := []struct {
 		name           string
 		setupMock      func(mock sqlmock.Sqlmock)
-		inputEntity    *domain.Domain
+		inputEntity    *domain.ProductPatch
 		expectedErr    bool
 		expectedResult *domain.Domain
 	}{
 		{
 			name: "Success",
-			inputEntity: &domain.Domain{
-				Name:        name,
-				Description: description,
-				Stock:       stock,
-				Price:       price,
+			inputEntity: &domain.ProductPatch{
+				Name:        &name,
+				Description: &description,
+				Price:       &price,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
-					WithArgs(name, description, stock, price, modified_at, id).
+				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?,\s*modified_by\s*=\s*\?,\s*version\s*=\s*version\s*\+\s*1\s+where\s+id\s*=\s*\?\s+and\s+version\s*=\s*\?\s+and\s+deleted_at\s+is\s+null\s*$`).
+					WithArgs(name, description, price, modified_at, nil, id, 3).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
-				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, created_at, modified_at from products where id = \?$`).
+				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, category_id, created_at, modified_at, version, deleted_at from products where id = \?$`).
 					WithArgs(id).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "stock", "price", "created_at", "modified_at"}).
-						AddRow(id, name, description, stock, price, time.Now(), modified_at))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "stock", "price", "category_id", "created_at", "modified_at", "version", "deleted_at"}).
+						AddRow(id, name, description, stock, price, nil, time.Now(), modified_at, 4, nil))
 			},
 			expectedErr: false,
 			expectedResult: &domain.Domain{
@@ -294,19 +444,44 @@ func TestUpdateProduct(t *testing.T) {
 				ModifiedAt:  &modified_at,
 			},
 		},
+		{
+			name: "Only supplied fields are set",
+			inputEntity: &domain.ProductPatch{
+				Price:      &price,
+				ModifiedAt: &modified_at,
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)^update\s+products\s+set\s+price\s*=\s*\?,\s*modified_at\s*=\s*\?,\s*modified_by\s*=\s*\?,\s*version\s*=\s*version\s*\+\s*1\s+where\s+id\s*=\s*\?\s+and\s+version\s*=\s*\?\s+and\s+deleted_at\s+is\s+null\s*$`).
+					WithArgs(price, modified_at, nil, id, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, category_id, created_at, modified_at, version, deleted_at from products where id = \?$`).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "stock", "price", "category_id", "created_at", "modified_at", "version", "deleted_at"}).
+						AddRow(id, "Product 1", nil, stock, price, nil, time.Now(), modified_at, 4, nil))
+			},
+			expectedErr: false,
+			expectedResult: &domain.Domain{
+				Id:         id,
+				Name:       "Product 1",
+				Stock:      stock,
+				Price:      price,
+				ModifiedAt: &modified_at,
+			},
+		},
 		{
 			name: "1 column missing",
-			inputEntity: &domain.Domain{
-				Name:        name,
-				Description: description,
-				Stock:       stock,
-				Price:       price,
+			inputEntity: &domain.ProductPatch{
+				Name:        &name,
+				Description: &description,
+				Price:       &price,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
-					WithArgs(name, description, stock, price, modified_at, id).
+				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?,\s*modified_by\s*=\s*\?,\s*version\s*=\s*version\s*\+\s*1\s+where\s+id\s*=\s*\?\s+and\s+version\s*=\s*\?\s+and\s+deleted_at\s+is\s+null\s*$`).
+					WithArgs(name, description, price, modified_at, nil, id, 3).
 					WillReturnError(errors.New("1 column missing"))
 			},
 			expectedErr:    true,
@@ -314,17 +489,16 @@ func TestUpdateProduct(t *testing.T) {
 		},
 		{
 			name: "Failed",
-			inputEntity: &domain.Domain{
-				Name:        name,
-				Description: description,
-				Stock:       stock,
-				Price:       price,
+			inputEntity: &domain.ProductPatch{
+				Name:        &name,
+				Description: &description,
+				Price:       &price,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
-					WithArgs(name, description, stock, price, modified_at, id).
+				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?,\s*modified_by\s*=\s*\?,\s*version\s*=\s*version\s*\+\s*1\s+where\s+id\s*=\s*\?\s+and\s+version\s*=\s*\?\s+and\s+deleted_at\s+is\s+null\s*$`).
+					WithArgs(name, description, price, modified_at, nil, id, 3).
 					WillReturnError(errors.New("failed to update product"))
 			},
 			expectedErr:    true,
@@ -332,11 +506,10 @@ func TestUpdateProduct(t *testing.T) {
 		},
 		{
 			name: "Tx failed",
-			inputEntity: &domain.Domain{
-				Name:        name,
-				Description: description,
-				Stock:       stock,
-				Price:       price,
+			inputEntity: &domain.ProductPatch{
+				Name:        &name,
+				Description: &description,
+				Price:       &price,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -347,11 +520,10 @@ func TestUpdateProduct(t *testing.T) {
 		},
 		{
 			name: "Connection failed",
-			inputEntity: &domain.Domain{
-				Name:        name,
-				Description: description,
-				Stock:       stock,
-				Price:       price,
+			inputEntity: &domain.ProductPatch{
+				Name:        &name,
+				Description: &description,
+				Price:       &price,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -362,17 +534,16 @@ func TestUpdateProduct(t *testing.T) {
 		},
 		{
 			name: "update but no rows affected",
-			inputEntity: &domain.Domain{
-				Name:        name,
-				Description: description,
-				Stock:       stock,
-				Price:       price,
+			inputEntity: &domain.ProductPatch{
+				Name:        &name,
+				Description: &description,
+				Price:       &price,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec(`(?i)^update\s+products`).
-					WithArgs(name, description, stock, price, modified_at, id).
+					WithArgs(name, description, price, modified_at, nil, id, 3).
 					WillReturnResult(sqlmock.NewResult(0, 0)) // No rows affected
 			},
 			expectedErr:    true,
@@ -380,20 +551,19 @@ func TestUpdateProduct(t *testing.T) {
 		},
 		{
 			name: "select after update failed",
-			inputEntity: &domain.Domain{
-				Name:        name,
-				Description: description,
-				Stock:       stock,
-				Price:       price,
+			inputEntity: &domain.ProductPatch{
+				Name:        &name,
+				Description: &description,
+				Price:       &price,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec(`(?i)^update\s+products`).
-					WithArgs(name, description, stock, price, modified_at, id).
+					WithArgs(name, description, price, modified_at, nil, id, 3).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
-				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, created_at, modified_at from products where id = \?$`).
+				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, category_id, created_at, modified_at, version, deleted_at from products where id = \?$`).
 					WithArgs(id).
 					WillReturnError(errors.New("select failed"))
 			},
@@ -418,7 +588,7 @@ func TestUpdateProduct(t *testing.T) {
 
 			repo := NewRepositoryImpl()
 
-			result, err := repo.UpdateProduct(context.Background(), tx, tt.inputEntity, id)
+			result, err := repo.UpdateProduct(context.Background(), tx, tt.inputEntity, id, 3)
 
 			if tt.expectedErr {
 				assert.Error(t, err)
@@ -427,10 +597,10 @@ func TestUpdateProduct(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 				assert.Equal(t, id, result.Id)
-				assert.Equal(t, tt.inputEntity.Name, result.Name)
-				assert.Equal(t, tt.inputEntity.Description, result.Description)
-				assert.Equal(t, tt.inputEntity.Stock, result.Stock)
-				assert.Equal(t, tt.inputEntity.Price, result.Price)
+				assert.Equal(t, tt.expectedResult.Name, result.Name)
+				assert.Equal(t, tt.expectedResult.Description, result.Description)
+				assert.Equal(t, tt.expectedResult.Stock, result.Stock)
+				assert.Equal(t, tt.expectedResult.Price, result.Price)
 				assert.WithinDuration(t, *tt.inputEntity.ModifiedAt, *result.ModifiedAt, time.Second)
 			}
 
@@ -439,64 +609,52 @@ func TestUpdateProduct(t *testing.T) {
 	}
 }
 
-func TestGetOrders(t *testing.T) {
-	createdAt := time.Now()
-	modifiedAt := time.Now()
-
+func TestMoveStock(t *testing.T) {
 	tests := []struct {
-		name           string
-		setupMock      func(mock sqlmock.Sqlmock)
-		expectedErr    bool
-		expectedResult []*domain.Orders
+		name         string
+		delta        int
+		mock         func(mock sqlmock.Sqlmock)
+		expectedCode string
+		expectedId   int64
 	}{
 		{
-			name: "success get orders",
-			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{
-					"id", "product_id", "product_name", "username", "quantity", "total", "status", "created_at", "modified_at",
-				}).AddRow(
-					"1", "101", "ProductA", "user1", 2, 100.0, "pending", createdAt, modifiedAt,
-				)
-
-				mock.ExpectQuery("SELECT \\* FROM orders").WillReturnRows(rows)
-			},
-			expectedErr: false,
-			expectedResult: []*domain.Orders{
-				{
-					Id:          "1",
-					ProductId:   "101",
-					ProductName: "ProductA",
-					Username:    "user1",
-					Quantity:    2,
-					Total:       100.0,
-					Status:      "pending",
-					CreatedAt:   &createdAt,
-					ModifiedAt:  &modifiedAt,
-				},
+			name:  "Recorded",
+			delta: -5,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^update products set stock = stock \+ \? where id = \? and \(\? > 0 or deleted_at is null\) and stock \+ \? >= 0$`).
+					WithArgs(-5, "PRD001", -5, -5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`(?i)^select stock from products where id = \?$`).
+					WithArgs("PRD001").
+					WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(45))
+				mock.ExpectExec(`(?i)^insert into stock_movements \(product_id, kind, delta, stock_after, reason, order_id, created_at, created_by\) values`).
+					WithArgs("PRD001", "waste", -5, 45, "Dropped tray", nil, sqlmock.AnyArg(), nil).
+					WillReturnResult(sqlmock.NewResult(12, 1))
 			},
+			expectedId: 12,
 		},
 		{
-			name: "order not found",
-			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{
-					"id", "product_id", "product_name", "username", "quantity", "total", "status", "created_at", "modified_at",
-				})
-				mock.ExpectQuery("SELECT \\* FROM orders").WillReturnRows(rows)
+			name:  "Not enough stock",
+			delta: -500,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^update products set stock`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)^select 1 from products where id = \? and deleted_at is null$`).
+					WithArgs("PRD001").
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 			},
-			expectedErr:    true,
-			expectedResult: nil,
+			expectedCode: "stock_conflict",
 		},
 		{
-			name: "data corrupted on scan",
-			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{
-					"id", "product_id", "product_name", "username", "quantity", "total", "status", "created_at", "modified_at",
-				}).AddRow("1", "101", "ProductA", "user1", "invalid", "total", "done", createdAt, modifiedAt)
-
-				mock.ExpectQuery("SELECT \\* FROM orders").WillReturnRows(rows)
+			name:  "Product missing",
+			delta: -5,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^update products set stock`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)^select 1 from products`).
+					WillReturnError(sql.ErrNoRows)
 			},
-			expectedErr:    true,
-			expectedResult: nil,
+			expectedCode: "product_not_found",
 		},
 	}
 
@@ -506,26 +664,24 @@ func TestGetOrders(t *testing.T) {
 			assert.NoError(t, err)
 			defer db.Close()
 
-			tt.setupMock(mock)
+			mock.ExpectBegin()
+			tt.mock(mock)
 
-			repo := NewRepositoryImpl()
-			result, err := repo.GetOrders(context.Background(), db)
+			tx, err := db.Begin()
+			assert.NoError(t, err)
 
-			if tt.expectedErr {
-				assert.Nil(t, result)
-			} else {
+			now := time.Now()
+			movement := &domain.StockMovement{ProductId: "PRD001", Kind: domain.StockWaste, Delta: tt.delta, Reason: "Dropped tray", CreatedAt: &now}
+			err = NewRepositoryImpl().MoveStock(context.Background(), tx, movement)
+			if tt.expectedCode == "" {
 				assert.NoError(t, err)
-				assert.NotNil(t, result)
-				assert.Equal(t, len(tt.expectedResult), len(result))
-				assert.Equal(t, tt.expectedResult[0].Id, result[0].Id)
-				assert.Equal(t, tt.expectedResult[0].ProductId, result[0].ProductId)
-				assert.Equal(t, tt.expectedResult[0].ProductName, result[0].ProductName)
-				assert.Equal(t, tt.expectedResult[0].Username, result[0].Username)
-				assert.Equal(t, tt.expectedResult[0].Quantity, result[0].Quantity)
-				assert.Equal(t, tt.expectedResult[0].Total, result[0].Total)
-				assert.Equal(t, tt.expectedResult[0].Status, result[0].Status)
-				assert.WithinDuration(t, *tt.expectedResult[0].CreatedAt, *result[0].CreatedAt, time.Second)
-				assert.WithinDuration(t, *tt.expectedResult[0].ModifiedAt, *result[0].ModifiedAt, time.Second)
+				assert.Equal(t, tt.expectedId, movement.Id)
+				assert.Equal(t, 45, movement.StockAfter)
+			} else {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedCode, domainErr.Code())
+				}
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -533,48 +689,43 @@ func TestGetOrders(t *testing.T) {
 	}
 }
 
-func TestUpdateOrder(t *testing.T) {
-	id := "1"
-	status := "done"
-
+func TestDeleteProduct(t *testing.T) {
 	tests := []struct {
-		name           string
-		setupMock      func(mock sqlmock.Sqlmock)
-		expectedErr    bool
-		expectedResult *domain.Orders
+		name         string
+		setupMock    func(mock sqlmock.Sqlmock)
+		expectedKind domain.ErrorKind
 	}{
 		{
-			name: "success update order",
+			name: "Deleted",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE orders SET status = \\? WHERE id = \\?").
-					WithArgs(status, id).
+				mock.ExpectExec(`(?i)^update products set deleted_at = \?, deleted_by = \?, version = version \+ 1 where id = \? and version = \? and deleted_at is null$`).
+					WithArgs(sqlmock.AnyArg(), nil, "PRD001", 2).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
-			expectedErr: false,
-			expectedResult: &domain.Orders{
-				Id:     id,
-				Status: status,
-			},
 		},
 		{
-			name: "update failed",
+			name: "Stale version",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE orders SET status = \\? WHERE id = \\?").
-					WithArgs(status, id).
-					WillReturnError(errors.New("update error"))
+				mock.ExpectExec(`(?i)^update products set deleted_at`).
+					WithArgs(sqlmock.AnyArg(), nil, "PRD001", 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)^select 1 from products where id = \? and deleted_at is null$`).
+					WithArgs("PRD001").
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 			},
-			expectedErr:    true,
-			expectedResult: nil,
+			expectedKind: domain.KindStale,
 		},
 		{
-			name: "transaction begin failed",
+			name: "Missing",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin().WillReturnError(errors.New("begin tx failed"))
+				mock.ExpectExec(`(?i)^update products set deleted_at`).
+					WithArgs(sqlmock.AnyArg(), nil, "PRD001", 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)^select 1 from products where id = \? and deleted_at is null$`).
+					WithArgs("PRD001").
+					WillReturnError(sql.ErrNoRows)
 			},
-			expectedErr:    true,
-			expectedResult: nil,
+			expectedKind: domain.KindNotFound,
 		},
 	}
 
@@ -584,26 +735,17 @@ func TestUpdateOrder(t *testing.T) {
 			assert.NoError(t, err)
 			defer db.Close()
 
+			mock.ExpectBegin()
 			tt.setupMock(mock)
 
 			tx, err := db.Begin()
-			if tt.expectedErr && tt.name == "transaction begin failed" {
-				assert.Error(t, err)
-				return
-			}
 			assert.NoError(t, err)
 
-			repo := NewRepositoryImpl()
-			order := &domain.Orders{
-				Id:     id,
-				Status: status,
-			}
-			err = repo.UpdateOrder(context.Background(), tx, order, id)
-
-			if tt.expectedErr {
-				assert.Error(t, err)
-			} else {
+			err = NewRepositoryImpl().DeleteProduct(context.Background(), tx, "PRD001", 2)
+			if tt.expectedKind == "" {
 				assert.NoError(t, err)
+			} else {
+				assert.True(t, domain.IsKind(err, tt.expectedKind), "got %v", err)
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -611,42 +753,1005 @@ func TestUpdateOrder(t *testing.T) {
 	}
 }
 
-func TestDeleteOrder(t *testing.T) {
-	id := "1"
+func TestRestoreProduct(t *testing.T) {
+	now := time.Now()
+
 	tests := []struct {
 		name        string
-		setupMock   func(sqlmock sqlmock.Sqlmock)
-		expectedErr bool
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr error
 	}{
 		{
-			name: "Success",
+			name: "Restored",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec("DELETE FROM orders WHERE id = \\?").
-					WithArgs(id).
+				mock.ExpectExec(`(?i)^update products set deleted_at = null, deleted_by = null, modified_at = \?, modified_by = \?, version = version \+ 1 where id = \? and deleted_at is not null$`).
+					WithArgs(sqlmock.AnyArg(), nil, "PRD001").
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`(?i)^select .* from products where id = \?$`).
+					WithArgs("PRD001").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "stock", "price", "category_id", "created_at", "modified_at", "version", "deleted_at"}).
+						AddRow("PRD001", "Nasi Box", "desc", 5, 2000, nil, now, now, 3, nil))
 			},
-			expectedErr: false,
+		},
+		{
+			name: "Not in the trash",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^update products set deleted_at = null`).
+					WithArgs(sqlmock.AnyArg(), nil, "PRD001").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: domain.NewError(domain.KindNotFound, "product", nil),
 		},
 	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			db, sqlmock, err := sqlmock.New()
-			if err != nil {
-				t.Fatal(err)
-				return
-			}
-			repo := NewRepositoryImpl()
-			tt.setupMock(sqlmock)
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.setupMock(mock)
+
 			tx, err := db.Begin()
-			if err != nil {
-				t.Fatal(err)
-				return
+			assert.NoError(t, err)
+
+			product, err := NewRepositoryImpl().RestoreProduct(context.Background(), tx, "PRD001")
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Nil(t, product.DeletedAt)
+				assert.Equal(t, 3, product.Version)
 			}
 
-			err = repo.DeleteOrder(context.Background(), tx, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPurgeDeletedProducts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	cutoff := time.Now().Add(-720 * time.Hour)
+	mock.ExpectExec(`(?i)^delete from products where products.deleted_at < \? and not exists \(select 1 from orders where orders.product_id = products.id\) and not exists \(select 1 from package_items where package_items.product_id = products.id\)$`).
+		WithArgs(cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	purged, err := NewRepositoryImpl().PurgeDeletedProducts(context.Background(), db, cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetProductImages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "product_id", "object_key", "thumbnail_key", "content_type", "size", "position", "created_at"}).
+		AddRow("img-1", "PRD001", "products/PRD001/img-1.png", "products/PRD001/img-1_thumb.png", "image/png", 1024, 0, now).
+		AddRow("img-2", "PRD002", "products/PRD002/img-2.jpg", "products/PRD002/img-2_thumb.jpg", "image/jpeg", 2048, 0, now)
+	mock.ExpectQuery(`(?i)^select .* from product_images where product_id in \(\?, \?\) order by product_id, position$`).
+		WithArgs("PRD001", "PRD002").
+		WillReturnRows(rows)
+
+	repo := NewRepositoryImpl()
+	images, err := repo.GetProductImages(context.Background(), db, []string{"PRD001", "PRD002"})
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+	assert.Equal(t, "products/PRD002/img-2_thumb.jpg", images[1].ThumbnailKey)
+
+	images, err = repo.GetProductImages(context.Background(), db, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, images)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteProductImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		mock        func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "product_id", "object_key", "thumbnail_key", "content_type", "size", "position", "created_at"}).
+					AddRow("img-1", "PRD001", "products/PRD001/img-1.png", "products/PRD001/img-1_thumb.png", "image/png", 1024, 0, time.Now())
+				mock.ExpectQuery(`(?i)^select .* from product_images where id = \? and product_id = \? for update$`).
+					WithArgs("img-1", "PRD001").
+					WillReturnRows(rows)
+				mock.ExpectExec(`(?i)^delete from product_images where id = \?$`).
+					WithArgs("img-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Image of another product",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)^select .* from product_images where id = \? and product_id = \? for update$`).
+					WithArgs("img-1", "PRD001").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.mock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			image, err := NewRepositoryImpl().DeleteProductImage(context.Background(), tx, "PRD001", "img-1")
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Equal(t, "image_not_found", err.(*domain.Error).Code())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "products/PRD001/img-1.png", image.Key)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAddProductVariant(t *testing.T) {
+	tests := []struct {
+		name         string
+		mock         func(mock sqlmock.Sqlmock)
+		expectedCode string
+	}{
+		{
+			name: "Success",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^insert into product_variants \(id, product_id, name, price_delta, stock, position\) select \?, id, \?, \?, \?, \? from products where id = \? and deleted_at is null$`).
+					WithArgs("variant-1", "Large", 5000, nil, 2, "PRD001").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Product missing or in the trash",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^insert into product_variants`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedCode: "product_not_found",
+		},
+		{
+			name: "Duplicate name",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^insert into product_variants`).
+					WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+			},
+			expectedCode: "variant_conflict",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.mock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			err = NewRepositoryImpl().AddProductVariant(context.Background(), tx, &domain.ProductVariant{
+				Id:         "variant-1",
+				ProductId:  "PRD001",
+				Name:       "Large",
+				PriceDelta: 5000,
+				Position:   2,
+			})
+			if tt.expectedCode == "" {
+				assert.NoError(t, err)
+			} else {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedCode, domainErr.Code())
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetOptionGroups(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`(?i)^select id, product_id, name, min_select, max_select, position from product_option_groups where product_id = \? order by position, name$`).
+		WithArgs("PRD001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "min_select", "max_select", "position"}).
+			AddRow("group-1", "PRD001", "Spice level", 1, 1, 0).
+			AddRow("group-2", "PRD001", "Extras", 0, 0, 1))
+	mock.ExpectQuery(`(?i)^select o.id, .* from product_options o join product_option_groups g on g.id = o.group_id where g.product_id = \? order by o.position, o.name$`).
+		WithArgs("PRD001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_id", "name", "price_delta", "stock", "position"}).
+			AddRow("option-1", "group-1", "Mild", 0, nil, 0).
+			AddRow("option-2", "group-1", "Extra hot", 0, nil, 1).
+			AddRow("option-3", "group-2", "Extra sambal", 2000, 30, 0))
+
+	groups, err := NewRepositoryImpl().GetOptionGroups(context.Background(), db, "PRD001")
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 2) {
+		assert.Len(t, groups[0].Options, 2)
+		assert.Equal(t, "Extra hot", groups[0].Options[1].Name)
+		if assert.Len(t, groups[1].Options, 1) {
+			assert.Equal(t, 30, *groups[1].Options[0].Stock)
+		}
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddPackage(t *testing.T) {
+	tests := []struct {
+		name         string
+		mock         func(mock sqlmock.Sqlmock)
+		expectedCode string
+	}{
+		{
+			name: "Success",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^insert into packages \(id, name, description, pricing, price, discount, created_at, modified_at, created_by\) values`).
+					WithArgs("PKG001", "Paket Hemat", "", "discount", 0, 50000, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`(?i)^insert into package_items \(package_id, product_id, quantity\) select \?, id, \? from products where id = \? and deleted_at is null$`).
+					WithArgs("PKG001", 100, "PRD001").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`(?i)^insert into package_items`).
+					WithArgs("PKG001", 100, "PRD002").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Item product missing or in the trash",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^insert into packages`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`(?i)^insert into package_items`).
+					WithArgs("PKG001", 100, "PRD001").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedCode: "product_validation_failed",
+		},
+		{
+			name: "Duplicate name",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^insert into packages`).
+					WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+			},
+			expectedCode: "package_conflict",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.mock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			now := time.Now()
+			err = NewRepositoryImpl().AddPackage(context.Background(), tx, &domain.Package{
+				Id:         "PKG001",
+				Name:       "Paket Hemat",
+				Pricing:    domain.PackagePricingDiscount,
+				Discount:   50000,
+				CreatedAt:  &now,
+				ModifiedAt: &now,
+				Items: []*domain.PackageItem{
+					{ProductId: "PRD001", Quantity: 100},
+					{ProductId: "PRD002", Quantity: 100},
+				},
+			})
+			if tt.expectedCode == "" {
+				assert.NoError(t, err)
+			} else {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedCode, domainErr.Code())
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetCategories(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "position", "created_at", "modified_at", "product_count"}).
+		AddRow(1, "Rice Boxes", nil, 0, now, now, 4).
+		AddRow(3, "Beverages", "Cold and hot drinks", 2, now, now, 0)
+	mock.ExpectQuery(`(?i)^select c.id, .*\(select count\(\*\) from products p where p.category_id = c.id and p.deleted_at is null\) from categories c order by c.position, c.name$`).
+		WillReturnRows(rows)
+
+	categories, err := NewRepositoryImpl().GetCategories(context.Background(), db)
+	assert.NoError(t, err)
+	if assert.Len(t, categories, 2) {
+		assert.Equal(t, 4, categories[0].ProductCount)
+		assert.Equal(t, "Cold and hot drinks", categories[1].Description)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		mock         func(mock sqlmock.Sqlmock)
+		expectedCode string
+	}{
+		{
+			name: "Success",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^delete from categories where id = \? and not exists \(select 1 from products where category_id = \? and deleted_at is null\)$`).
+					WithArgs(2, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Still has products",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^delete from categories`).
+					WithArgs(2, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)^select 1 from categories where id = \?$`).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			},
+			expectedCode: "category_in_use",
+		},
+		{
+			name: "Not found",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`(?i)^delete from categories`).
+					WithArgs(2, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)^select 1 from categories where id = \?$`).
+					WithArgs(2).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedCode: "category_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.mock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			err = NewRepositoryImpl().DeleteCategory(context.Background(), tx, 2)
+			if tt.expectedCode == "" {
+				assert.NoError(t, err)
+			} else {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedCode, domainErr.Code())
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetOrders(t *testing.T) {
+	createdAt := time.Now()
+	modifiedAt := time.Now()
+	variantId, variantName := "variant-1", "Large"
+
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedResult []*domain.Orders
+	}{
+		{
+			name: "success get orders",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{
+					"id", "product_id", "package_id", "product_name", "variant_id", "variant_name", "username", "quantity", "total", "status", "stock_reserved", "created_at", "modified_at", "version",
+				}).AddRow(
+					"1", "101", nil, "ProductA", "variant-1", "Large", "user1", 2, 100.0, "pending", false, createdAt, modifiedAt, 1,
+				)
+
+				mock.ExpectQuery("(?i)select .* from orders").WillReturnRows(rows)
+			},
+			expectedErr: false,
+			expectedResult: []*domain.Orders{
+				{
+					Id:          "1",
+					ProductId:   "101",
+					ProductName: "ProductA",
+					VariantId:   &variantId,
+					VariantName: &variantName,
+					Username:    "user1",
+					Quantity:    2,
+					Total:       100.0,
+					Status:      "pending",
+					CreatedAt:   &createdAt,
+					ModifiedAt:  &modifiedAt,
+				},
+			},
+		},
+		{
+			name: "order not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{
+					"id", "product_id", "package_id", "product_name", "variant_id", "variant_name", "username", "quantity", "total", "status", "stock_reserved", "created_at", "modified_at", "version",
+				})
+				mock.ExpectQuery("(?i)select .* from orders").WillReturnRows(rows)
+			},
+			expectedErr:    true,
+			expectedResult: nil,
+		},
+		{
+			name: "data corrupted on scan",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{
+					"id", "product_id", "package_id", "product_name", "variant_id", "variant_name", "username", "quantity", "total", "status", "stock_reserved", "created_at", "modified_at", "version",
+				}).AddRow("1", "101", nil, "ProductA", nil, nil, "user1", "invalid", "total", "done", false, createdAt, modifiedAt, 1)
+
+				mock.ExpectQuery("(?i)select .* from orders").WillReturnRows(rows)
+			},
+			expectedErr:    true,
+			expectedResult: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewRepositoryImpl()
+			result, err := repo.GetOrders(context.Background(), db, &domain.OrderFilter{Limit: 20})
+
+			if tt.expectedErr {
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+				assert.Equal(t, len(tt.expectedResult), len(result))
+				assert.Equal(t, tt.expectedResult[0].Id, result[0].Id)
+				assert.Equal(t, tt.expectedResult[0].ProductId, result[0].ProductId)
+				assert.Equal(t, tt.expectedResult[0].ProductName, result[0].ProductName)
+				assert.Equal(t, tt.expectedResult[0].Username, result[0].Username)
+				assert.Equal(t, tt.expectedResult[0].Quantity, result[0].Quantity)
+				assert.Equal(t, tt.expectedResult[0].Total, result[0].Total)
+				assert.Equal(t, tt.expectedResult[0].Status, result[0].Status)
+				assert.WithinDuration(t, *tt.expectedResult[0].CreatedAt, *result[0].CreatedAt, time.Second)
+				assert.WithinDuration(t, *tt.expectedResult[0].ModifiedAt, *result[0].ModifiedAt, time.Second)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetOrdersFilters(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	cursorAt := time.Date(2025, 1, 15, 8, 30, 0, 0, time.UTC)
+	minTotal := 50000.0
+	columns := []string{"id", "product_id", "package_id", "product_name", "variant_id", "variant_name", "username", "quantity", "total", "status", "stock_reserved", "created_at", "modified_at", "version"}
+
+	tests := []struct {
+		name      string
+		filter    *domain.OrderFilter
+		setupMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:   "Status, customer, product, dates and total",
+			filter: &domain.OrderFilter{Status: "pending", Username: "user1", ProductId: "PRD001", CreatedFrom: &from, CreatedBefore: &before, MinTotal: &minTotal, Limit: 20},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)^select id, product_id, package_id, product_name, variant_id, variant_name, username, quantity, total, status, stock_reserved, created_at, modified_at, version from orders where status = \? and username = \? and product_id = \? and created_at >= \? and created_at < \? and total >= \? order by created_at desc, id desc limit \?$`).
+					WithArgs("pending", "user1", "PRD001", from, before, minTotal, 20).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name:   "Cursor",
+			filter: &domain.OrderFilter{Limit: 5, After: &domain.Cursor{Value: cursorAt.Format(time.RFC3339Nano), Id: "order-9"}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)^select .* from orders where \(created_at < \? or \(created_at = \? and id < \?\)\) order by created_at desc, id desc limit \?$`).
+					WithArgs(cursorAt, cursorAt, "order-9", 5).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			_, err = NewRepositoryImpl().GetOrders(context.Background(), db, tt.filter)
+			assert.NoError(t, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateOrder(t *testing.T) {
+	id := "1"
+	status := "done"
+
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedResult *domain.Orders
+	}{
+		{
+			name: "success update order",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status = \\?, stock_reserved = \\?, modified_by = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\?").
+					WithArgs(status, false, nil, id, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: false,
+			expectedResult: &domain.Orders{
+				Id:     id,
+				Status: status,
+			},
+		},
+		{
+			name: "update failed",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status = \\?, stock_reserved = \\?, modified_by = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\?").
+					WithArgs(status, false, nil, id, 3).
+					WillReturnError(errors.New("update error"))
+			},
+			expectedErr:    true,
+			expectedResult: nil,
+		},
+		{
+			name: "transaction begin failed",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(errors.New("begin tx failed"))
+			},
+			expectedErr:    true,
+			expectedResult: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			if tt.expectedErr && tt.name == "transaction begin failed" {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			repo := NewRepositoryImpl()
+			order := &domain.Orders{
+				Id:     id,
+				Status: status,
+			}
+			err = repo.UpdateOrder(context.Background(), tx, order, id, 3)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetOrderStockLines(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)^select product_id, product_name, quantity from orders where id = \? and product_id is not null union all select i.product_id, p.name, i.quantity \* o.quantity from orders o join package_items i .* where o.id = \? order by product_id$`).
+		WithArgs("order-1", "order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "product_name", "quantity"}).
+			AddRow("PRD001", "Nasi Box", 200).
+			AddRow("PRD002", "Es Teh", 200))
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	lines, err := NewRepositoryImpl().GetOrderStockLines(context.Background(), tx, "order-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.StockLine{
+		{ProductId: "PRD001", ProductName: "Nasi Box", Quantity: 200},
+		{ProductId: "PRD002", ProductName: "Es Teh", Quantity: 200},
+	}, lines)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrderReservedStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)^select m.product_id, p.name, -sum\(m.delta\) from stock_movements m join products p on p.id = m.product_id where m.order_id = \? and m.kind in \('reservation', 'restock'\) group by m.product_id, p.name having sum\(m.delta\) < 0 order by m.product_id$`).
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "name", "quantity"}).
+			AddRow("PRD001", "Nasi Box", 20))
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	lines, err := NewRepositoryImpl().GetOrderReservedStock(context.Background(), tx, "order-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.StockLine{{ProductId: "PRD001", ProductName: "Nasi Box", Quantity: 20}}, lines)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReserveOrderVariantStock(t *testing.T) {
+	tests := []struct {
+		name          string
+		shortRows     *sqlmock.Rows
+		expectedShort string
+	}{
+		{
+			name:          "Reserved",
+			shortRows:     sqlmock.NewRows([]string{"name"}),
+			expectedShort: "",
+		},
+		{
+			name:          "Variant is short",
+			shortRows:     sqlmock.NewRows([]string{"name"}).AddRow("Large"),
+			expectedShort: "Large",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`(?i)^update product_variants v join orders o on o.variant_id = v.id set v.stock = v.stock - o.quantity, o.variant_reserved = o.quantity where o.id = \? and v.stock >= o.quantity$`).
+				WithArgs("order-1").
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(`(?i)^update product_options p join order_options oo .* set p.stock = p.stock - o.quantity, oo.stock_reserved = o.quantity where o.id = \? and p.stock >= o.quantity$`).
+				WithArgs("order-1").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(`(?i)^select v.name from orders o join product_variants v .* union all select p.name from order_options oo .* limit 1$`).
+				WithArgs("order-1", "order-1").
+				WillReturnRows(tt.shortRows)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			short, err := NewRepositoryImpl().ReserveOrderVariantStock(context.Background(), tx, "order-1")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedShort, short)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReleaseOrderVariantStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)^update product_variants v join orders o on o.variant_id = v.id set v.stock = v.stock \+ o.variant_reserved where o.id = \? and o.variant_reserved > 0$`).
+		WithArgs("order-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?i)^update product_options p join order_options oo on oo.option_id = p.id set p.stock = p.stock \+ oo.stock_reserved where oo.order_id = \? and oo.stock_reserved > 0$`).
+		WithArgs("order-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`(?i)^update orders set variant_reserved = 0 where id = \?$`).
+		WithArgs("order-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?i)^update order_options set stock_reserved = 0 where order_id = \?$`).
+		WithArgs("order-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	err = NewRepositoryImpl().ReleaseOrderVariantStock(context.Background(), tx, "order-1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateOrderStampsActor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	adminId := "e7b8a9d4-3f5a-4c82-b7e2-2c3f49b0e9c1"
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE orders SET status = \\?, stock_reserved = \\?, modified_by = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\?").
+		WithArgs("done", false, adminId, "1", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	ctx := helper.WithClaims(context.Background(), &helper.Claims{
+		Username:         "admin",
+		RegisteredClaims: jwt.RegisteredClaims{Subject: adminId},
+	})
+	err = NewRepositoryImpl().UpdateOrder(ctx, tx, &domain.Orders{Status: "done"}, "1", 3)
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddOrderStatusChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	date := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO order_status_history \\(order_id, from_status, to_status, username, note, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, NULLIF\\(\\?, ''\\), \\?\\)").
+		WithArgs("1", "pending", "confirmed", "admin", "Paid by transfer", &date).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	ctx := helper.WithClaims(context.Background(), &helper.Claims{
+		Username:         "admin",
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "e7b8a9d4-3f5a-4c82-b7e2-2c3f49b0e9c1"},
+	})
+	change := &domain.OrderStatusChange{OrderId: "1", FromStatus: "pending", ToStatus: "confirmed", Note: "Paid by transfer", CreatedAt: &date}
+	err = NewRepositoryImpl().AddOrderStatusChange(ctx, tx, change)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), change.Id)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrderStatusHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	date := time.Now()
+	mock.ExpectQuery("SELECT id, order_id, from_status, to_status, username, note, created_at FROM order_status_history WHERE order_id = \\? ORDER BY id").
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "from_status", "to_status", "username", "note", "created_at"}).
+			AddRow(1, "1", "pending", "confirmed", "admin", nil, date).
+			AddRow(2, "1", "confirmed", "cancelled", nil, "Customer called", date))
+
+	history, err := NewRepositoryImpl().GetOrderStatusHistory(context.Background(), db, "1")
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "admin", *history[0].Username)
+	assert.Equal(t, "", history[0].Note)
+	assert.Nil(t, history[1].Username)
+	assert.Equal(t, "Customer called", history[1].Note)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOrder(t *testing.T) {
+	id := "1"
+	tests := []struct {
+		name        string
+		setupMock   func(sqlmock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM orders WHERE id = \\? AND version = \\?").
+					WithArgs(id, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlmock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+				return
+			}
+			repo := NewRepositoryImpl()
+			tt.setupMock(sqlmock)
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatal(err)
+				return
+			}
+
+			err = repo.DeleteOrder(context.Background(), tx, id, 3)
 			assert.Nil(t, err)
 			sqlmock.ExpectationsWereMet()
 		})
 	}
 }
+
+func TestRevokeRefreshToken(t *testing.T) {
+	id := "123e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)^update refresh_tokens set revoked_at = \? where id = \? and revoked_at is null$`).
+					WithArgs(sqlmock.AnyArg(), id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Already revoked",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)^update refresh_tokens set revoked_at`).
+					WithArgs(sqlmock.AnyArg(), id).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			repo := NewRepositoryImpl()
+			err = repo.RevokeRefreshToken(context.Background(), tx, id)
+			assert.Equal(t, tt.expectedErr, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIsAccessTokenRevoked(t *testing.T) {
+	adminId := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+	tests := []struct {
+		name     string
+		revoked  bool
+		expected bool
+	}{
+		{name: "Active token of an enabled admin", revoked: false, expected: false},
+		{name: "Revoked jti or disabled admin", revoked: true, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(`(?i)^select exists\(select 1 from revoked_access_tokens where jti = \?\) or not exists\(select 1 from admin where id = \? and disabled_at is null\)$`).
+				WithArgs("jti-1", adminId).
+				WillReturnRows(sqlmock.NewRows([]string{"revoked"}).AddRow(tt.revoked))
+
+			repo := NewRepositoryImpl()
+			revoked, err := repo.IsAccessTokenRevoked(context.Background(), db, "jti-1", adminId)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, revoked)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedKind domain.ErrorKind
+	}{
+		{name: "No rows", err: sql.ErrNoRows, expectedKind: domain.KindNotFound},
+		{name: "Duplicate name", err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Nasi Box' for key 'products.name'"}, expectedKind: domain.KindConflict},
+		{name: "Referenced by orders", err: &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"}, expectedKind: domain.KindForeignKeyInUse},
+		{name: "Missing parent", err: &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, expectedKind: domain.KindValidation},
+		{name: "Other driver error", err: &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}},
+		{name: "Connection error", err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError("product", tt.err)
+			assert.ErrorIs(t, err, tt.err)
+
+			var domainErr *domain.Error
+			if tt.expectedKind == "" {
+				assert.False(t, errors.As(err, &domainErr))
+				return
+			}
+			if assert.True(t, errors.As(err, &domainErr)) {
+				assert.Equal(t, tt.expectedKind, domainErr.Kind)
+				assert.Equal(t, "product_"+string(tt.expectedKind), domainErr.Code())
+			}
+		})
+	}
+}
+
+func TestTranslateProductError(t *testing.T) {
+	err := translateProductError(&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"})
+	var domainErr *domain.Error
+	if assert.ErrorAs(t, err, &domainErr) {
+		assert.Equal(t, "category_validation_failed", domainErr.Code())
+		assert.Equal(t, "Category does not exist.", domainErr.Message)
+	}
+
+	err = translateProductError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+	assert.True(t, domain.IsKind(err, domain.KindConflict))
+}
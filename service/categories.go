@@ -0,0 +1,96 @@
+package service
+
+import (
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"catering-admin-go/logger"
+	"catering-admin-go/web"
+	"context"
+	"time"
+)
+
+func (svc *ServiceImpl) GetCategories(ctx context.Context) ([]*domain.Category, error) {
+	categories, err := svc.repo.GetCategories(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get categories", "error", err.Error())
+		return nil, err
+	}
+	if categories == nil {
+		categories = []*domain.Category{}
+	}
+
+	return categories, nil
+}
+
+func (svc *ServiceImpl) GetCategory(ctx context.Context, id int) (*domain.Category, error) {
+	category, err := svc.repo.GetCategory(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get category", "error", err.Error())
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (svc *ServiceImpl) AddCategory(ctx context.Context, request *web.CategoryRequest) (data *domain.Category, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add category", "error", err.Error())
+		return nil, err
+	}
+
+	date := time.Now()
+	defer helper.WithTransaction(tx, &err)
+	data, err = svc.repo.AddCategory(ctx, tx, &domain.Category{
+		Name:        request.Name,
+		Description: request.Description,
+		Position:    request.Position,
+		CreatedAt:   &date,
+		ModifiedAt:  &date,
+	})
+	if err != nil {
+		logger.GetLogger("service-log").Log("add category", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) UpdateCategory(ctx context.Context, request *web.CategoryRequest, id int) (data *domain.Category, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update category", "error", err.Error())
+		return nil, err
+	}
+
+	date := time.Now()
+	defer helper.WithTransaction(tx, &err)
+	data, err = svc.repo.UpdateCategory(ctx, tx, &domain.Category{
+		Name:        request.Name,
+		Description: request.Description,
+		Position:    request.Position,
+		ModifiedAt:  &date,
+	}, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update category", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) DeleteCategory(ctx context.Context, id int) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete category", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	if err = svc.repo.DeleteCategory(ctx, tx, id); err != nil {
+		logger.GetLogger("service-log").Log("delete category", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"catering-admin-go/domain"
+	"errors"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid username or password")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+	ErrAdminNotFound       = errors.New("admin not found")
+	ErrUsernameTaken       = errors.New("username already taken")
+	ErrIncorrectPassword   = errors.New("current password is incorrect")
+	ErrCannotModifySelf    = errors.New("cannot disable or delete your own account")
+	ErrUnauthenticated     = errors.New("no authenticated admin in context")
+
+	// Repository errors of the same kind and entity match these with errors.Is.
+	ErrProductNotFound  = domain.NewError(domain.KindNotFound, "product", nil)
+	ErrOrderNotFound    = domain.NewError(domain.KindNotFound, "order", nil)
+	ErrCategoryNotFound = domain.NewError(domain.KindNotFound, "category", nil)
+	ErrPackageNotFound  = domain.NewError(domain.KindNotFound, "package", nil)
+
+	ErrInvalidImage  = &domain.Error{Kind: domain.KindValidation, Entity: "image", Message: "Images must be JPEG or PNG files of at most 5 MB."}
+	ErrTooManyImages = &domain.Error{Kind: domain.KindValidation, Entity: "image", Message: "At most 10 images can be uploaded at once."}
+
+	ErrInsufficientStock      = &domain.Error{Kind: domain.KindConflict, Entity: "stock", Message: "Not enough stock to fulfil this order."}
+	ErrUnknownOrderStatus     = &domain.Error{Kind: domain.KindValidation, Entity: "order_status", Message: "Status must be one of pending, confirmed, preparing, ready, delivering, completed, cancelled or refunded."}
+	ErrInvalidOrderTransition = &domain.Error{Kind: domain.KindConflict, Entity: "order_status", Message: "The order cannot move to that status."}
+	ErrInvalidStockMovement   = &domain.Error{Kind: domain.KindValidation, Entity: "stock_movement", Message: "Waste must take stock away; send a negative delta."}
+	ErrInvalidOptionGroup     = &domain.Error{Kind: domain.KindValidation, Entity: "option_group", Message: "max_select must be 0 for no limit or at least min_select."}
+
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotSetUp       = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+)
@@ -4,40 +4,901 @@ package mocks
 
 import (
 	domain "catering-admin-go/domain"
+	web "catering-admin-go/web"
 	context "context"
+	uuid "github.com/google/uuid"
+	multipart "mime/multipart"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Service is an autogenerated mock type for the Service type
+type Service struct {
+	mock.Mock
+}
+
+// AddAdmin provides a mock function with given fields: ctx, request
+func (_m *Service) AddAdmin(ctx context.Context, request *web.CreateAdminRequest) (*domain.Admin, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAdmin")
+	}
+
+	var r0 *domain.Admin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *web.CreateAdminRequest) (*domain.Admin, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *web.CreateAdminRequest) *domain.Admin); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Admin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *web.CreateAdminRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddCategory provides a mock function with given fields: ctx, request
+func (_m *Service) AddCategory(ctx context.Context, request *web.CategoryRequest) (*domain.Category, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCategory")
+	}
+
+	var r0 *domain.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *web.CategoryRequest) (*domain.Category, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *web.CategoryRequest) *domain.Category); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *web.CategoryRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddOptionGroup provides a mock function with given fields: ctx, productId, request
+func (_m *Service) AddOptionGroup(ctx context.Context, productId string, request *web.OptionGroupRequest) (*domain.OptionGroup, error) {
+	ret := _m.Called(ctx, productId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddOptionGroup")
+	}
+
+	var r0 *domain.OptionGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *web.OptionGroupRequest) (*domain.OptionGroup, error)); ok {
+		return rf(ctx, productId, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *web.OptionGroupRequest) *domain.OptionGroup); ok {
+		r0 = rf(ctx, productId, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OptionGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *web.OptionGroupRequest) error); ok {
+		r1 = rf(ctx, productId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddPackage provides a mock function with given fields: ctx, request
+func (_m *Service) AddPackage(ctx context.Context, request *web.PackageRequest) (*domain.Package, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddPackage")
+	}
+
+	var r0 *domain.Package
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *web.PackageRequest) (*domain.Package, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *web.PackageRequest) *domain.Package); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *web.PackageRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddProduct provides a mock function with given fields: ctx, request, images
+func (_m *Service) AddProduct(ctx context.Context, request *web.Request, images []*multipart.FileHeader) (*domain.Domain, error) {
+	ret := _m.Called(ctx, request, images)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddProduct")
+	}
+
+	var r0 *domain.Domain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *web.Request, []*multipart.FileHeader) (*domain.Domain, error)); ok {
+		return rf(ctx, request, images)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *web.Request, []*multipart.FileHeader) *domain.Domain); ok {
+		r0 = rf(ctx, request, images)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *web.Request, []*multipart.FileHeader) error); ok {
+		r1 = rf(ctx, request, images)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddProductOption provides a mock function with given fields: ctx, productId, groupId, request
+func (_m *Service) AddProductOption(ctx context.Context, productId string, groupId string, request *web.OptionRequest) (*domain.ProductOption, error) {
+	ret := _m.Called(ctx, productId, groupId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddProductOption")
+	}
+
+	var r0 *domain.ProductOption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *web.OptionRequest) (*domain.ProductOption, error)); ok {
+		return rf(ctx, productId, groupId, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *web.OptionRequest) *domain.ProductOption); ok {
+		r0 = rf(ctx, productId, groupId, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductOption)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *web.OptionRequest) error); ok {
+		r1 = rf(ctx, productId, groupId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddProductVariant provides a mock function with given fields: ctx, productId, request
+func (_m *Service) AddProductVariant(ctx context.Context, productId string, request *web.VariantRequest) (*domain.ProductVariant, error) {
+	ret := _m.Called(ctx, productId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddProductVariant")
+	}
+
+	var r0 *domain.ProductVariant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *web.VariantRequest) (*domain.ProductVariant, error)); ok {
+		return rf(ctx, productId, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *web.VariantRequest) *domain.ProductVariant); ok {
+		r0 = rf(ctx, productId, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductVariant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *web.VariantRequest) error); ok {
+		r1 = rf(ctx, productId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddStockMovement provides a mock function with given fields: ctx, productId, request
+func (_m *Service) AddStockMovement(ctx context.Context, productId string, request *web.StockMovementRequest) (*domain.StockMovement, error) {
+	ret := _m.Called(ctx, productId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddStockMovement")
+	}
+
+	var r0 *domain.StockMovement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *web.StockMovementRequest) (*domain.StockMovement, error)); ok {
+		return rf(ctx, productId, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *web.StockMovementRequest) *domain.StockMovement); ok {
+		r0 = rf(ctx, productId, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.StockMovement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *web.StockMovementRequest) error); ok {
+		r1 = rf(ctx, productId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangePassword provides a mock function with given fields: ctx, request
+func (_m *Service) ChangePassword(ctx context.Context, request *web.ChangePasswordRequest) error {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *web.ChangePasswordRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAdmin provides a mock function with given fields: ctx, id
+func (_m *Service) DeleteAdmin(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAdmin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCategory provides a mock function with given fields: ctx, id
+func (_m *Service) DeleteCategory(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOptionGroup provides a mock function with given fields: ctx, productId, groupId
+func (_m *Service) DeleteOptionGroup(ctx context.Context, productId string, groupId string) error {
+	ret := _m.Called(ctx, productId, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOptionGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, productId, groupId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOrder provides a mock function with given fields: ctx, id, version
+func (_m *Service) DeleteOrder(ctx context.Context, id string, version int) error {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePackage provides a mock function with given fields: ctx, id
+func (_m *Service) DeletePackage(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePackage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProduct provides a mock function with given fields: ctx, id, version
+func (_m *Service) DeleteProduct(ctx context.Context, id string, version int) error {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProductImage provides a mock function with given fields: ctx, productId, imageId
+func (_m *Service) DeleteProductImage(ctx context.Context, productId string, imageId string) error {
+	ret := _m.Called(ctx, productId, imageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProductImage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, productId, imageId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProductOption provides a mock function with given fields: ctx, productId, groupId, optionId
+func (_m *Service) DeleteProductOption(ctx context.Context, productId string, groupId string, optionId string) error {
+	ret := _m.Called(ctx, productId, groupId, optionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProductOption")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, productId, groupId, optionId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProductVariant provides a mock function with given fields: ctx, productId, variantId
+func (_m *Service) DeleteProductVariant(ctx context.Context, productId string, variantId string) error {
+	ret := _m.Called(ctx, productId, variantId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProductVariant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, productId, variantId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableAdmin provides a mock function with given fields: ctx, id
+func (_m *Service) DisableAdmin(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableAdmin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTwoFactor provides a mock function with given fields: ctx, request
+func (_m *Service) DisableTwoFactor(ctx context.Context, request *web.DisableTwoFactorRequest) error {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTwoFactor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *web.DisableTwoFactorRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableAdmin provides a mock function with given fields: ctx, id
+func (_m *Service) EnableAdmin(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableAdmin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTwoFactor provides a mock function with given fields: ctx, request
+func (_m *Service) EnableTwoFactor(ctx context.Context, request *web.TwoFactorCodeRequest) (*web.RecoveryCodesResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTwoFactor")
+	}
+
+	var r0 *web.RecoveryCodesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *web.TwoFactorCodeRequest) (*web.RecoveryCodesResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *web.TwoFactorCodeRequest) *web.RecoveryCodesResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*web.RecoveryCodesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *web.TwoFactorCodeRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAdmins provides a mock function with given fields: ctx
+func (_m *Service) GetAdmins(ctx context.Context) ([]*domain.Admin, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAdmins")
+	}
+
+	var r0 []*domain.Admin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Admin, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Admin); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Admin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategories provides a mock function with given fields: ctx
+func (_m *Service) GetCategories(ctx context.Context) ([]*domain.Category, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategories")
+	}
+
+	var r0 []*domain.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Category, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Category); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategory provides a mock function with given fields: ctx, id
+func (_m *Service) GetCategory(ctx context.Context, id int) (*domain.Category, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategory")
+	}
+
+	var r0 *domain.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*domain.Category, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *domain.Category); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrder provides a mock function with given fields: ctx, id
+func (_m *Service) GetOrder(ctx context.Context, id string) (*web.OrderDetailResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 *web.OrderDetailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*web.OrderDetailResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *web.OrderDetailResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*web.OrderDetailResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrders provides a mock function with given fields: ctx, filter
+func (_m *Service) GetOrders(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Orders, *web.Meta, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrders")
+	}
+
+	var r0 []*domain.Orders
+	var r1 *web.Meta
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OrderFilter) ([]*domain.Orders, *web.Meta, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OrderFilter) []*domain.Orders); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Orders)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.OrderFilter) *web.Meta); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*web.Meta)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.OrderFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPackage provides a mock function with given fields: ctx, id
+func (_m *Service) GetPackage(ctx context.Context, id string) (*domain.Package, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPackage")
+	}
+
+	var r0 *domain.Package
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Package, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Package); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPackages provides a mock function with given fields: ctx
+func (_m *Service) GetPackages(ctx context.Context) ([]*domain.Package, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPackages")
+	}
+
+	var r0 []*domain.Package
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Package, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Package); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProduct provides a mock function with given fields: ctx, id
+func (_m *Service) GetProduct(ctx context.Context, id string) (*web.ProductDetailResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProduct")
+	}
+
+	var r0 *web.ProductDetailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*web.ProductDetailResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *web.ProductDetailResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*web.ProductDetailResponse)
+		}
+	}
 
-	mock "github.com/stretchr/testify/mock"
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
 
-	web "catering-admin-go/web"
-)
+	return r0, r1
+}
 
-// Service is an autogenerated mock type for the Service type
-type Service struct {
-	mock.Mock
+// GetProducts provides a mock function with given fields: ctx, filter
+func (_m *Service) GetProducts(ctx context.Context, filter *domain.ProductFilter) ([]*domain.Domain, *web.Meta, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProducts")
+	}
+
+	var r0 []*domain.Domain
+	var r1 *web.Meta
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProductFilter) ([]*domain.Domain, *web.Meta, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProductFilter) []*domain.Domain); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ProductFilter) *web.Meta); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*web.Meta)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.ProductFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetStockMovements provides a mock function with given fields: ctx, filter
+func (_m *Service) GetStockMovements(ctx context.Context, filter *domain.StockMovementFilter) ([]*domain.StockMovement, *web.Meta, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStockMovements")
+	}
+
+	var r0 []*domain.StockMovement
+	var r1 *web.Meta
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.StockMovementFilter) ([]*domain.StockMovement, *web.Meta, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.StockMovementFilter) []*domain.StockMovement); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.StockMovement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.StockMovementFilter) *web.Meta); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*web.Meta)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.StockMovementFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IsTokenRevoked provides a mock function with given fields: ctx, jti, adminId
+func (_m *Service) IsTokenRevoked(ctx context.Context, jti string, adminId uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, jti, adminId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (bool, error)); ok {
+		return rf(ctx, jti, adminId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) bool); ok {
+		r0 = rf(ctx, jti, adminId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = rf(ctx, jti, adminId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// AddProduct provides a mock function with given fields: ctx, request
-func (_m *Service) AddProduct(ctx context.Context, request *web.Request) (*domain.Domain, error) {
+// Login provides a mock function with given fields: ctx, request
+func (_m *Service) Login(ctx context.Context, request *domain.Admin) (*web.AdminResponse, error) {
 	ret := _m.Called(ctx, request)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddProduct")
+		panic("no return value specified for Login")
 	}
 
-	var r0 *domain.Domain
+	var r0 *web.AdminResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *web.Request) (*domain.Domain, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Admin) (*web.AdminResponse, error)); ok {
 		return rf(ctx, request)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *web.Request) *domain.Domain); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Admin) *web.AdminResponse); ok {
 		r0 = rf(ctx, request)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Domain)
+			r0 = ret.Get(0).(*web.AdminResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *web.Request) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Admin) error); ok {
 		r1 = rf(ctx, request)
 	} else {
 		r1 = ret.Error(1)
@@ -46,17 +907,17 @@ func (_m *Service) AddProduct(ctx context.Context, request *web.Request) (*domai
 	return r0, r1
 }
 
-// DeleteOrder provides a mock function with given fields: ctx, id
-func (_m *Service) DeleteOrder(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// Logout provides a mock function with given fields: ctx, refreshToken
+func (_m *Service) Logout(ctx context.Context, refreshToken string) error {
+	ret := _m.Called(ctx, refreshToken)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteOrder")
+		panic("no return value specified for Logout")
 	}
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+		r0 = rf(ctx, refreshToken)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -64,17 +925,75 @@ func (_m *Service) DeleteOrder(ctx context.Context, id string) error {
 	return r0
 }
 
-// DeleteProduct provides a mock function with given fields: ctx, id
-func (_m *Service) DeleteProduct(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// PurgeDeletedProducts provides a mock function with given fields: ctx, retention
+func (_m *Service) PurgeDeletedProducts(ctx context.Context, retention time.Duration) (int64, error) {
+	ret := _m.Called(ctx, retention)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteProduct")
+		panic("no return value specified for PurgeDeletedProducts")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (int64, error)); ok {
+		return rf(ctx, retention)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int64); ok {
+		r0 = rf(ctx, retention)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: ctx, refreshToken
+func (_m *Service) RefreshToken(ctx context.Context, refreshToken string) (*web.AdminResponse, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *web.AdminResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*web.AdminResponse, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *web.AdminResponse); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*web.AdminResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetAdminPassword provides a mock function with given fields: ctx, id, request
+func (_m *Service) ResetAdminPassword(ctx context.Context, id uuid.UUID, request *web.ResetPasswordRequest) error {
+	ret := _m.Called(ctx, id, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetAdminPassword")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *web.ResetPasswordRequest) error); ok {
+		r0 = rf(ctx, id, request)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -82,29 +1001,29 @@ func (_m *Service) DeleteProduct(ctx context.Context, id string) error {
 	return r0
 }
 
-// GetOrders provides a mock function with given fields: ctx
-func (_m *Service) GetOrders(ctx context.Context) ([]*domain.Orders, error) {
-	ret := _m.Called(ctx)
+// RestoreProduct provides a mock function with given fields: ctx, id
+func (_m *Service) RestoreProduct(ctx context.Context, id string) (*domain.Domain, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOrders")
+		panic("no return value specified for RestoreProduct")
 	}
 
-	var r0 []*domain.Orders
+	var r0 *domain.Domain
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Orders, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Domain, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Orders); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Domain); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Orders)
+			r0 = ret.Get(0).(*domain.Domain)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -112,24 +1031,24 @@ func (_m *Service) GetOrders(ctx context.Context) ([]*domain.Orders, error) {
 	return r0, r1
 }
 
-// GetProducts provides a mock function with given fields: ctx
-func (_m *Service) GetProducts(ctx context.Context) ([]*domain.Domain, error) {
+// SetupTwoFactor provides a mock function with given fields: ctx
+func (_m *Service) SetupTwoFactor(ctx context.Context) (*web.TwoFactorSetupResponse, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetProducts")
+		panic("no return value specified for SetupTwoFactor")
 	}
 
-	var r0 []*domain.Domain
+	var r0 *web.TwoFactorSetupResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Domain, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) (*web.TwoFactorSetupResponse, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Domain); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) *web.TwoFactorSetupResponse); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Domain)
+			r0 = ret.Get(0).(*web.TwoFactorSetupResponse)
 		}
 	}
 
@@ -142,29 +1061,59 @@ func (_m *Service) GetProducts(ctx context.Context) ([]*domain.Domain, error) {
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, request
-func (_m *Service) Login(ctx context.Context, request *domain.Admin) (*web.AdminResponse, error) {
-	ret := _m.Called(ctx, request)
+// UpdateCategory provides a mock function with given fields: ctx, request, id
+func (_m *Service) UpdateCategory(ctx context.Context, request *web.CategoryRequest, id int) (*domain.Category, error) {
+	ret := _m.Called(ctx, request, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for UpdateCategory")
 	}
 
-	var r0 *web.AdminResponse
+	var r0 *domain.Category
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Admin) (*web.AdminResponse, error)); ok {
-		return rf(ctx, request)
+	if rf, ok := ret.Get(0).(func(context.Context, *web.CategoryRequest, int) (*domain.Category, error)); ok {
+		return rf(ctx, request, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Admin) *web.AdminResponse); ok {
-		r0 = rf(ctx, request)
+	if rf, ok := ret.Get(0).(func(context.Context, *web.CategoryRequest, int) *domain.Category); ok {
+		r0 = rf(ctx, request, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*web.AdminResponse)
+			r0 = ret.Get(0).(*domain.Category)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.Admin) error); ok {
-		r1 = rf(ctx, request)
+	if rf, ok := ret.Get(1).(func(context.Context, *web.CategoryRequest, int) error); ok {
+		r1 = rf(ctx, request, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOptionGroup provides a mock function with given fields: ctx, productId, groupId, request
+func (_m *Service) UpdateOptionGroup(ctx context.Context, productId string, groupId string, request *web.OptionGroupRequest) (*domain.OptionGroup, error) {
+	ret := _m.Called(ctx, productId, groupId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOptionGroup")
+	}
+
+	var r0 *domain.OptionGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *web.OptionGroupRequest) (*domain.OptionGroup, error)); ok {
+		return rf(ctx, productId, groupId, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *web.OptionGroupRequest) *domain.OptionGroup); ok {
+		r0 = rf(ctx, productId, groupId, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OptionGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *web.OptionGroupRequest) error); ok {
+		r1 = rf(ctx, productId, groupId, request)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -172,17 +1121,17 @@ func (_m *Service) Login(ctx context.Context, request *domain.Admin) (*web.Admin
 	return r0, r1
 }
 
-// UpdateOrder provides a mock function with given fields: ctx, entity, id
-func (_m *Service) UpdateOrder(ctx context.Context, entity *domain.Orders, id string) error {
-	ret := _m.Called(ctx, entity, id)
+// UpdateOrder provides a mock function with given fields: ctx, entity, id, version
+func (_m *Service) UpdateOrder(ctx context.Context, entity *domain.Orders, id string, version int) error {
+	ret := _m.Called(ctx, entity, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateOrder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Orders, string) error); ok {
-		r0 = rf(ctx, entity, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Orders, string, int) error); ok {
+		r0 = rf(ctx, entity, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -190,9 +1139,39 @@ func (_m *Service) UpdateOrder(ctx context.Context, entity *domain.Orders, id st
 	return r0
 }
 
-// UpdateProduct provides a mock function with given fields: ctx, request, id
-func (_m *Service) UpdateProduct(ctx context.Context, request *web.Request, id string) (*domain.Domain, error) {
-	ret := _m.Called(ctx, request, id)
+// UpdatePackage provides a mock function with given fields: ctx, id, request
+func (_m *Service) UpdatePackage(ctx context.Context, id string, request *web.PackageRequest) (*domain.Package, error) {
+	ret := _m.Called(ctx, id, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePackage")
+	}
+
+	var r0 *domain.Package
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *web.PackageRequest) (*domain.Package, error)); ok {
+		return rf(ctx, id, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *web.PackageRequest) *domain.Package); ok {
+		r0 = rf(ctx, id, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Package)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *web.PackageRequest) error); ok {
+		r1 = rf(ctx, id, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, request, images, id, version
+func (_m *Service) UpdateProduct(ctx context.Context, request *web.UpdateProductRequest, images []*multipart.FileHeader, id string, version int) (*domain.Domain, error) {
+	ret := _m.Called(ctx, request, images, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateProduct")
@@ -200,19 +1179,109 @@ func (_m *Service) UpdateProduct(ctx context.Context, request *web.Request, id s
 
 	var r0 *domain.Domain
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *web.Request, string) (*domain.Domain, error)); ok {
-		return rf(ctx, request, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *web.UpdateProductRequest, []*multipart.FileHeader, string, int) (*domain.Domain, error)); ok {
+		return rf(ctx, request, images, id, version)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *web.Request, string) *domain.Domain); ok {
-		r0 = rf(ctx, request, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *web.UpdateProductRequest, []*multipart.FileHeader, string, int) *domain.Domain); ok {
+		r0 = rf(ctx, request, images, id, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Domain)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *web.Request, string) error); ok {
-		r1 = rf(ctx, request, id)
+	if rf, ok := ret.Get(1).(func(context.Context, *web.UpdateProductRequest, []*multipart.FileHeader, string, int) error); ok {
+		r1 = rf(ctx, request, images, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProductOption provides a mock function with given fields: ctx, productId, groupId, optionId, request
+func (_m *Service) UpdateProductOption(ctx context.Context, productId string, groupId string, optionId string, request *web.OptionRequest) (*domain.ProductOption, error) {
+	ret := _m.Called(ctx, productId, groupId, optionId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProductOption")
+	}
+
+	var r0 *domain.ProductOption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *web.OptionRequest) (*domain.ProductOption, error)); ok {
+		return rf(ctx, productId, groupId, optionId, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *web.OptionRequest) *domain.ProductOption); ok {
+		r0 = rf(ctx, productId, groupId, optionId, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductOption)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *web.OptionRequest) error); ok {
+		r1 = rf(ctx, productId, groupId, optionId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProductVariant provides a mock function with given fields: ctx, productId, variantId, request
+func (_m *Service) UpdateProductVariant(ctx context.Context, productId string, variantId string, request *web.VariantRequest) (*domain.ProductVariant, error) {
+	ret := _m.Called(ctx, productId, variantId, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProductVariant")
+	}
+
+	var r0 *domain.ProductVariant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *web.VariantRequest) (*domain.ProductVariant, error)); ok {
+		return rf(ctx, productId, variantId, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *web.VariantRequest) *domain.ProductVariant); ok {
+		r0 = rf(ctx, productId, variantId, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProductVariant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *web.VariantRequest) error); ok {
+		r1 = rf(ctx, productId, variantId, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyTwoFactor provides a mock function with given fields: ctx, request
+func (_m *Service) VerifyTwoFactor(ctx context.Context, request *web.TwoFactorLoginRequest) (*web.AdminResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyTwoFactor")
+	}
+
+	var r0 *web.AdminResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *web.TwoFactorLoginRequest) (*web.AdminResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *web.TwoFactorLoginRequest) *web.AdminResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*web.AdminResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *web.TwoFactorLoginRequest) error); ok {
+		r1 = rf(ctx, request)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"catering-admin-go/domain"
+	"fmt"
+	"slices"
+)
+
+const (
+	orderStatusPending    = "pending"
+	orderStatusConfirmed  = "confirmed"
+	orderStatusPreparing  = "preparing"
+	orderStatusReady      = "ready"
+	orderStatusDelivering = "delivering"
+	orderStatusCompleted  = "completed"
+	orderStatusCancelled  = "cancelled"
+	orderStatusRefunded   = "refunded"
+)
+
+// orderTransitions is the order lifecycle: the statuses an order in each
+// status may move to next. An order can be cancelled until it leaves the
+// kitchen, and refunded once it is completed or cancelled.
+var orderTransitions = map[string][]string{
+	orderStatusPending:    {orderStatusConfirmed, orderStatusCancelled},
+	orderStatusConfirmed:  {orderStatusPreparing, orderStatusCancelled},
+	orderStatusPreparing:  {orderStatusReady, orderStatusCancelled},
+	orderStatusReady:      {orderStatusDelivering, orderStatusCancelled},
+	orderStatusDelivering: {orderStatusCompleted},
+	orderStatusCompleted:  {orderStatusRefunded},
+	orderStatusCancelled:  {orderStatusRefunded},
+	orderStatusRefunded:   {},
+}
+
+// checkOrderTransition fails with ErrUnknownOrderStatus for a status outside
+// the lifecycle and with ErrInvalidOrderTransition when to does not follow
+// from.
+func checkOrderTransition(from string, to string) error {
+	if _, ok := orderTransitions[to]; !ok {
+		return ErrUnknownOrderStatus
+	}
+	if !slices.Contains(orderTransitions[from], to) {
+		return &domain.Error{
+			Kind:    ErrInvalidOrderTransition.Kind,
+			Entity:  ErrInvalidOrderTransition.Entity,
+			Message: fmt.Sprintf("An order cannot go from %s to %s.", from, to),
+		}
+	}
+	return nil
+}
+
+// holdsStock reports whether an order in status keeps stock reserved: from
+// the moment it is confirmed until it is cancelled. Completed and refunded
+// orders have used their stock up.
+func holdsStock(status string) bool {
+	switch status {
+	case orderStatusConfirmed, orderStatusPreparing, orderStatusReady, orderStatusDelivering:
+		return true
+	default:
+		return false
+	}
+}
+
+// allowNextStatuses tells clients which statuses each order can move to.
+func allowNextStatuses(orders ...*domain.Orders) {
+	for _, order := range orders {
+		order.AllowedNextStatuses = slices.Clone(orderTransitions[order.Status])
+		if order.AllowedNextStatuses == nil {
+			order.AllowedNextStatuses = []string{}
+		}
+	}
+}
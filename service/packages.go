@@ -0,0 +1,165 @@
+package service
+
+import (
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"catering-admin-go/logger"
+	"catering-admin-go/web"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	packageSequence = "packages"
+	packageIdPrefix = "PKG"
+)
+
+// priceBundle fills in the price of a discount package and how many packages
+// the stock of its items covers.
+func priceBundle(pkg *domain.Package) {
+	sum := 0
+	available := -1
+	for _, item := range pkg.Items {
+		sum += item.UnitPrice * item.Quantity
+
+		covered := item.Stock / item.Quantity
+		if item.Deleted || covered < 0 {
+			covered = 0
+		}
+		if available < 0 || covered < available {
+			available = covered
+		}
+	}
+
+	if pkg.Pricing == domain.PackagePricingDiscount {
+		pkg.Price = max(sum-pkg.Discount, 0)
+	}
+	pkg.Available = max(available, 0)
+}
+
+func (svc *ServiceImpl) GetPackages(ctx context.Context) ([]*domain.Package, error) {
+	packages, err := svc.repo.GetPackages(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get packages", "error", err.Error())
+		return nil, err
+	}
+	if packages == nil {
+		packages = []*domain.Package{}
+	}
+
+	for _, pkg := range packages {
+		priceBundle(pkg)
+	}
+	return packages, nil
+}
+
+func (svc *ServiceImpl) GetPackage(ctx context.Context, id string) (*domain.Package, error) {
+	pkg, err := svc.repo.GetPackage(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get package", "error", err.Error())
+		return nil, err
+	}
+
+	priceBundle(pkg)
+	return pkg, nil
+}
+
+// bundle builds the package a request describes. Price is only kept for
+// fixed pricing and Discount only for discount pricing.
+func bundle(id string, request *web.PackageRequest) *domain.Package {
+	date := time.Now()
+	pkg := &domain.Package{
+		Id:          id,
+		Name:        request.Name,
+		Description: request.Description,
+		Pricing:     request.Pricing,
+		CreatedAt:   &date,
+		ModifiedAt:  &date,
+	}
+	if request.Pricing == domain.PackagePricingFixed {
+		pkg.Price = request.Price
+	} else {
+		pkg.Discount = request.Discount
+	}
+
+	for _, item := range request.Items {
+		pkg.Items = append(pkg.Items, &domain.PackageItem{ProductId: item.ProductId, Quantity: item.Quantity})
+	}
+	return pkg
+}
+
+func (svc *ServiceImpl) AddPackage(ctx context.Context, request *web.PackageRequest) (*domain.Package, error) {
+	id, err := svc.addPackage(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.GetPackage(ctx, id)
+}
+
+// addPackage writes the package and returns its id; the package is read back
+// once the transaction has committed.
+func (svc *ServiceImpl) addPackage(ctx context.Context, request *web.PackageRequest) (id string, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add package", "error", err.Error())
+		return "", err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	seq, err := svc.repo.NextSequenceValue(ctx, tx, packageSequence)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add package", "error", err.Error())
+		return "", err
+	}
+	id = fmt.Sprintf("%s%03d", packageIdPrefix, seq)
+
+	if err = svc.repo.AddPackage(ctx, tx, bundle(id, request)); err != nil {
+		logger.GetLogger("service-log").Log("add package", "error", err.Error())
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (svc *ServiceImpl) UpdatePackage(ctx context.Context, id string, request *web.PackageRequest) (*domain.Package, error) {
+	if err := svc.updatePackage(ctx, id, request); err != nil {
+		return nil, err
+	}
+
+	return svc.GetPackage(ctx, id)
+}
+
+func (svc *ServiceImpl) updatePackage(ctx context.Context, id string, request *web.PackageRequest) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update package", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	if err = svc.repo.UpdatePackage(ctx, tx, bundle(id, request)); err != nil {
+		logger.GetLogger("service-log").Log("update package", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (svc *ServiceImpl) DeletePackage(ctx context.Context, id string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete package", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	if err = svc.repo.DeletePackage(ctx, tx, id); err != nil {
+		logger.GetLogger("service-log").Log("delete package", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
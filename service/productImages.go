@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"catering-admin-go/logger"
+	"context"
+	"database/sql"
+	"io"
+	"mime/multipart"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxImageSize      = 5 << 20
+	MaxImagesPerWrite = 10
+)
+
+// storeImages validates uploads and writes each image and its thumbnail to
+// storage. Files already written are removed again if a later one fails.
+// Keys depend only on the image id so new products can store their images
+// before an id is taken from the sequence.
+func (svc *ServiceImpl) storeImages(ctx context.Context, files []*multipart.FileHeader) (images []*domain.ProductImage, err error) {
+	if len(files) > MaxImagesPerWrite {
+		return nil, ErrTooManyImages
+	}
+	defer func() {
+		if err != nil {
+			svc.deleteImageFiles(ctx, images)
+			images = nil
+		}
+	}()
+
+	for _, file := range files {
+		if file.Size > MaxImageSize {
+			return images, ErrInvalidImage
+		}
+		data, err := readUpload(file)
+		if err != nil {
+			return images, err
+		}
+
+		contentType, ext, err := helper.DetectImage(data)
+		if err != nil {
+			return images, ErrInvalidImage
+		}
+		thumbnail, err := helper.MakeThumbnail(data, contentType)
+		if err != nil {
+			return images, ErrInvalidImage
+		}
+
+		id := uuid.NewString()
+		image := &domain.ProductImage{
+			Id:           id,
+			Key:          "products/" + id + ext,
+			ThumbnailKey: "products/" + id + "_thumb" + ext,
+			ContentType:  contentType,
+			Size:         len(data),
+		}
+		if err := svc.store.Put(ctx, image.Key, bytes.NewReader(data), contentType); err != nil {
+			return images, err
+		}
+		if err := svc.store.Put(ctx, image.ThumbnailKey, bytes.NewReader(thumbnail), contentType); err != nil {
+			svc.deleteImageFiles(ctx, []*domain.ProductImage{image})
+			return images, err
+		}
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+func readUpload(file *multipart.FileHeader) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImageSize {
+		return nil, ErrInvalidImage
+	}
+	return data, nil
+}
+
+func (svc *ServiceImpl) addImages(ctx context.Context, tx *sql.Tx, productId string, images []*domain.ProductImage) error {
+	for _, image := range images {
+		image.ProductId = productId
+		if err := svc.repo.AddProductImage(ctx, tx, image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteImageFiles is best effort: a leftover file costs disk space but
+// must not fail the request that already changed the database.
+func (svc *ServiceImpl) deleteImageFiles(ctx context.Context, images []*domain.ProductImage) {
+	for _, image := range images {
+		for _, key := range []string{image.Key, image.ThumbnailKey} {
+			if err := svc.store.Delete(ctx, key); err != nil {
+				logger.GetLogger("service-log").Log("delete image file", "error", err.Error())
+			}
+		}
+	}
+}
+
+// attachImages loads the images of products and fills in their URLs.
+func (svc *ServiceImpl) attachImages(ctx context.Context, products ...*domain.Domain) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(products))
+	byId := make(map[string]*domain.Domain, len(products))
+	for i, product := range products {
+		ids[i] = product.Id
+		byId[product.Id] = product
+		product.Images = []*domain.ProductImage{}
+	}
+
+	images, err := svc.repo.GetProductImages(ctx, svc.db, ids)
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		image.URL = svc.store.URL(image.Key)
+		image.ThumbnailURL = svc.store.URL(image.ThumbnailKey)
+		if product, ok := byId[image.ProductId]; ok {
+			product.Images = append(product.Images, image)
+		}
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) DeleteProductImage(ctx context.Context, productId string, imageId string) error {
+	image, err := svc.deleteImageRow(ctx, productId, imageId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete product image", "error", err.Error())
+		return err
+	}
+
+	// Only remove the files once the row is gone for good.
+	svc.deleteImageFiles(ctx, []*domain.ProductImage{image})
+	return nil
+}
+
+func (svc *ServiceImpl) deleteImageRow(ctx context.Context, productId string, imageId string) (image *domain.ProductImage, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	return svc.repo.DeleteProductImage(ctx, tx, productId, imageId)
+}
@@ -4,15 +4,59 @@ import (
 	"catering-admin-go/domain"
 	"catering-admin-go/web"
 	"context"
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type Service interface {
 	Login(ctx context.Context, request *domain.Admin) (*web.AdminResponse, error)
-	AddProduct(ctx context.Context, request *web.Request) (*domain.Domain, error)
-	GetProducts(ctx context.Context) ([]*domain.Domain, error)
-	DeleteProduct(ctx context.Context, id string) error
-	UpdateProduct(ctx context.Context, request *web.Request, id string) (*domain.Domain, error)
-	GetOrders(ctx context.Context) ([]*domain.Orders, error)
-	UpdateOrder(ctx context.Context, entity *domain.Orders, id string) error
-	DeleteOrder(ctx context.Context, id string) error
+	VerifyTwoFactor(ctx context.Context, request *web.TwoFactorLoginRequest) (*web.AdminResponse, error)
+	SetupTwoFactor(ctx context.Context) (*web.TwoFactorSetupResponse, error)
+	EnableTwoFactor(ctx context.Context, request *web.TwoFactorCodeRequest) (*web.RecoveryCodesResponse, error)
+	DisableTwoFactor(ctx context.Context, request *web.DisableTwoFactorRequest) error
+	RefreshToken(ctx context.Context, refreshToken string) (*web.AdminResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+	IsTokenRevoked(ctx context.Context, jti string, adminId uuid.UUID) (bool, error)
+	GetAdmins(ctx context.Context) ([]*domain.Admin, error)
+	AddAdmin(ctx context.Context, request *web.CreateAdminRequest) (*domain.Admin, error)
+	DisableAdmin(ctx context.Context, id uuid.UUID) error
+	EnableAdmin(ctx context.Context, id uuid.UUID) error
+	DeleteAdmin(ctx context.Context, id uuid.UUID) error
+	ChangePassword(ctx context.Context, request *web.ChangePasswordRequest) error
+	ResetAdminPassword(ctx context.Context, id uuid.UUID, request *web.ResetPasswordRequest) error
+	AddProduct(ctx context.Context, request *web.Request, images []*multipart.FileHeader) (*domain.Domain, error)
+	GetProduct(ctx context.Context, id string) (*web.ProductDetailResponse, error)
+	GetProducts(ctx context.Context, filter *domain.ProductFilter) ([]*domain.Domain, *web.Meta, error)
+	DeleteProduct(ctx context.Context, id string, version int) error
+	UpdateProduct(ctx context.Context, request *web.UpdateProductRequest, images []*multipart.FileHeader, id string, version int) (*domain.Domain, error)
+	DeleteProductImage(ctx context.Context, productId string, imageId string) error
+	AddStockMovement(ctx context.Context, productId string, request *web.StockMovementRequest) (*domain.StockMovement, error)
+	GetStockMovements(ctx context.Context, filter *domain.StockMovementFilter) ([]*domain.StockMovement, *web.Meta, error)
+	RestoreProduct(ctx context.Context, id string) (*domain.Domain, error)
+	PurgeDeletedProducts(ctx context.Context, retention time.Duration) (int64, error)
+	AddProductVariant(ctx context.Context, productId string, request *web.VariantRequest) (*domain.ProductVariant, error)
+	UpdateProductVariant(ctx context.Context, productId string, variantId string, request *web.VariantRequest) (*domain.ProductVariant, error)
+	DeleteProductVariant(ctx context.Context, productId string, variantId string) error
+	AddOptionGroup(ctx context.Context, productId string, request *web.OptionGroupRequest) (*domain.OptionGroup, error)
+	UpdateOptionGroup(ctx context.Context, productId string, groupId string, request *web.OptionGroupRequest) (*domain.OptionGroup, error)
+	DeleteOptionGroup(ctx context.Context, productId string, groupId string) error
+	AddProductOption(ctx context.Context, productId string, groupId string, request *web.OptionRequest) (*domain.ProductOption, error)
+	UpdateProductOption(ctx context.Context, productId string, groupId string, optionId string, request *web.OptionRequest) (*domain.ProductOption, error)
+	DeleteProductOption(ctx context.Context, productId string, groupId string, optionId string) error
+	GetCategories(ctx context.Context) ([]*domain.Category, error)
+	GetCategory(ctx context.Context, id int) (*domain.Category, error)
+	AddCategory(ctx context.Context, request *web.CategoryRequest) (*domain.Category, error)
+	UpdateCategory(ctx context.Context, request *web.CategoryRequest, id int) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, id int) error
+	GetPackages(ctx context.Context) ([]*domain.Package, error)
+	GetPackage(ctx context.Context, id string) (*domain.Package, error)
+	AddPackage(ctx context.Context, request *web.PackageRequest) (*domain.Package, error)
+	UpdatePackage(ctx context.Context, id string, request *web.PackageRequest) (*domain.Package, error)
+	DeletePackage(ctx context.Context, id string) error
+	GetOrder(ctx context.Context, id string) (*web.OrderDetailResponse, error)
+	GetOrders(ctx context.Context, filter *domain.OrderFilter) ([]*domain.Orders, *web.Meta, error)
+	UpdateOrder(ctx context.Context, entity *domain.Orders, id string, version int) error
+	DeleteOrder(ctx context.Context, id string, version int) error
 }
@@ -5,40 +5,684 @@ import (
 	"catering-admin-go/helper"
 	"catering-admin-go/logger"
 	"catering-admin-go/repository"
+	"catering-admin-go/storage"
 	"catering-admin-go/web"
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	productSequence = "products"
+	productIdPrefix = "PRD"
+
+	recentOrdersLimit = 5
+)
+
+type ServiceImpl struct {
+	repo  repository.Repository
+	db    *sql.DB
+	store storage.Storage
+}
+
+func NewServiceImpl(repo repository.Repository, db *sql.DB, store storage.Storage) Service {
+	return &ServiceImpl{
+		repo:  repo,
+		db:    db,
+		store: store,
+	}
+}
+
+func (svc *ServiceImpl) Login(ctx context.Context, request *domain.Admin) (response *web.AdminResponse, err error) {
+	result, err := svc.repo.Login(ctx, svc.db, request)
+	if err == sql.ErrNoRows {
+		helper.ComparePasswordDummy(request.Password)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		logger.GetLogger("service-log").Log("login", "error", err.Error())
+		return nil, err
+	}
+
+	if err := helper.ComparePassword(result.Password, request.Password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if result.DisabledAt != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if result.TwoFactorEnabled {
+		challengeToken, err := helper.GenerateChallengeToken(result.Id)
+		if err != nil {
+			logger.GetLogger("service-log").Log("login", "error", err.Error())
+			return nil, err
+		}
+
+		return &web.AdminResponse{
+			Username:          result.Username,
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, nil
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("login", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	response, err = svc.issueTokens(ctx, tx, result, uuid.NewString())
+	if err != nil {
+		logger.GetLogger("service-log").Log("login", "error", err.Error())
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (svc *ServiceImpl) VerifyTwoFactor(ctx context.Context, request *web.TwoFactorLoginRequest) (response *web.AdminResponse, err error) {
+	adminId, err := helper.ParseChallengeToken(request.ChallengeToken)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	admin, err := svc.repo.GetAdminById(ctx, svc.db, adminId)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.GetLogger("service-log").Log("verify two factor", "error", err.Error())
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if admin.DisabledAt != nil || !admin.TwoFactorEnabled {
+		return nil, ErrInvalidCredentials
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("verify two factor", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	if request.Code != "" {
+		err = svc.consumeTOTP(ctx, tx, admin, request.Code)
+	} else {
+		err = svc.repo.UseRecoveryCode(ctx, tx, admin.Id, helper.HashToken(helper.NormalizeRecoveryCode(request.RecoveryCode)))
+		if err == sql.ErrNoRows {
+			err = ErrInvalidTwoFactorCode
+		}
+	}
+	if err != nil {
+		if err != ErrInvalidTwoFactorCode {
+			logger.GetLogger("service-log").Log("verify two factor", "error", err.Error())
+		}
+		return nil, err
+	}
+
+	response, err = svc.issueTokens(ctx, tx, admin, uuid.NewString())
+	if err != nil {
+		logger.GetLogger("service-log").Log("verify two factor", "error", err.Error())
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (svc *ServiceImpl) SetupTwoFactor(ctx context.Context) (response *web.TwoFactorSetupResponse, err error) {
+	admin, err := svc.currentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := helper.GenerateTOTPSecret()
+	if err != nil {
+		logger.GetLogger("service-log").Log("setup two factor", "error", err.Error())
+		return nil, err
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("setup two factor", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	err = svc.repo.UpdateAdminTOTP(ctx, tx, admin.Id, secret, false)
+	if err != nil {
+		logger.GetLogger("service-log").Log("setup two factor", "error", err.Error())
+		return nil, err
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "Catering Admin"
+	}
+
+	return &web.TwoFactorSetupResponse{
+		Secret:     secret,
+		OtpauthURI: helper.TOTPURI(issuer, admin.Username, secret),
+	}, nil
+}
+
+func (svc *ServiceImpl) EnableTwoFactor(ctx context.Context, request *web.TwoFactorCodeRequest) (response *web.RecoveryCodesResponse, err error) {
+	admin, err := svc.currentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if admin.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotSetUp
+	}
+
+	codes, err := helper.GenerateRecoveryCodes(10)
+	if err != nil {
+		logger.GetLogger("service-log").Log("enable two factor", "error", err.Error())
+		return nil, err
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("enable two factor", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	err = svc.repo.UpdateAdminTOTP(ctx, tx, admin.Id, admin.TOTPSecret, true)
+	if err != nil {
+		logger.GetLogger("service-log").Log("enable two factor", "error", err.Error())
+		return nil, err
+	}
+
+	err = svc.consumeTOTP(ctx, tx, admin, request.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, helper.HashToken(code))
+	}
+	err = svc.repo.ReplaceRecoveryCodes(ctx, tx, admin.Id, hashes)
+	if err != nil {
+		logger.GetLogger("service-log").Log("enable two factor", "error", err.Error())
+		return nil, err
+	}
+
+	return &web.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (svc *ServiceImpl) DisableTwoFactor(ctx context.Context, request *web.DisableTwoFactorRequest) (err error) {
+	admin, err := svc.currentAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !admin.TwoFactorEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if err := helper.ComparePassword(admin.Password, request.Password); err != nil {
+		return ErrIncorrectPassword
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("disable two factor", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	err = svc.consumeTOTP(ctx, tx, admin, request.Code)
+	if err != nil {
+		return err
+	}
+
+	err = svc.repo.UpdateAdminTOTP(ctx, tx, admin.Id, "", false)
+	if err != nil {
+		logger.GetLogger("service-log").Log("disable two factor", "error", err.Error())
+		return err
+	}
+
+	err = svc.repo.ReplaceRecoveryCodes(ctx, tx, admin.Id, nil)
+	if err != nil {
+		logger.GetLogger("service-log").Log("disable two factor", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// consumeTOTP accepts each time step at most once per admin.
+func (svc *ServiceImpl) consumeTOTP(ctx context.Context, tx *sql.Tx, admin *domain.Admin, code string) error {
+	step, ok := helper.ValidateTOTP(admin.TOTPSecret, code, time.Now())
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	err := svc.repo.UpdateAdminTOTPStep(ctx, tx, admin.Id, step)
+	if err == sql.ErrNoRows {
+		return ErrInvalidTwoFactorCode
+	}
+	return err
+}
+
+// currentAdmin loads the admin behind the access token the request was
+// authenticated with.
+func (svc *ServiceImpl) currentAdmin(ctx context.Context) (*domain.Admin, error) {
+	claims, ok := helper.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	id, err := claims.AdminId()
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+
+	admin, err := svc.repo.GetAdminById(ctx, svc.db, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrAdminNotFound
+	}
+	if err != nil {
+		logger.GetLogger("service-log").Log("get admin", "error", err.Error())
+		return nil, err
+	}
+
+	return admin, nil
+}
+
+func (svc *ServiceImpl) RefreshToken(ctx context.Context, refreshToken string) (*web.AdminResponse, error) {
+	stored, err := svc.repo.GetRefreshToken(ctx, svc.db, helper.HashToken(refreshToken))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.GetLogger("service-log").Log("refresh token", "error", err.Error())
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		if err := svc.revokeReusedFamily(ctx, stored); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	admin, err := svc.repo.GetAdminById(ctx, svc.db, stored.AdminId)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.GetLogger("service-log").Log("refresh token", "error", err.Error())
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if admin.DisabledAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	response, err := svc.rotateRefreshToken(ctx, stored, admin)
+	if err == sql.ErrNoRows {
+		// Another request rotated this token first, which is reuse as well.
+		if err := svc.revokeReusedFamily(ctx, stored); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		logger.GetLogger("service-log").Log("refresh token", "error", err.Error())
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (svc *ServiceImpl) Logout(ctx context.Context, refreshToken string) (err error) {
+	claims, ok := helper.ClaimsFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	var stored *domain.RefreshToken
+	if refreshToken != "" {
+		stored, err = svc.repo.GetRefreshToken(ctx, svc.db, helper.HashToken(refreshToken))
+		if err != nil && err != sql.ErrNoRows {
+			logger.GetLogger("service-log").Log("logout", "error", err.Error())
+			return err
+		}
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("logout", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	err = svc.repo.AddRevokedAccessToken(ctx, tx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		logger.GetLogger("service-log").Log("logout", "error", err.Error())
+		return err
+	}
+
+	if stored != nil {
+		err = svc.repo.RevokeRefreshTokenFamily(ctx, tx, stored.FamilyId)
+		if err != nil {
+			logger.GetLogger("service-log").Log("logout", "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *ServiceImpl) IsTokenRevoked(ctx context.Context, jti string, adminId uuid.UUID) (bool, error) {
+	revoked, err := svc.repo.IsAccessTokenRevoked(ctx, svc.db, jti, adminId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("check revoked token", "error", err.Error())
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+func (svc *ServiceImpl) GetAdmins(ctx context.Context) ([]*domain.Admin, error) {
+	admins, err := svc.repo.GetAdmins(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get admins", "error", err.Error())
+		return nil, err
+	}
+
+	return admins, nil
+}
+
+func (svc *ServiceImpl) AddAdmin(ctx context.Context, request *web.CreateAdminRequest) (data *domain.Admin, err error) {
+	_, err = svc.repo.Login(ctx, svc.db, &domain.Admin{Username: request.Username})
+	if err == nil {
+		return nil, ErrUsernameTaken
+	}
+	if err != sql.ErrNoRows {
+		logger.GetLogger("service-log").Log("add admin", "error", err.Error())
+		return nil, err
+	}
+
+	hash, err := helper.HashPassword(request.Password)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add admin", "error", err.Error())
+		return nil, err
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add admin", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	date := time.Now()
+	data, err = svc.repo.AddAdmin(ctx, tx, &domain.Admin{
+		Id:        uuid.New(),
+		Username:  request.Username,
+		Password:  hash,
+		Role:      request.Role,
+		CreatedAt: &date,
+	})
+	if err != nil {
+		logger.GetLogger("service-log").Log("add admin", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) DisableAdmin(ctx context.Context, id uuid.UUID) (err error) {
+	if err := svc.ensureNotSelf(ctx, id); err != nil {
+		return err
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("disable admin", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	date := time.Now()
+	err = svc.repo.UpdateAdminStatus(ctx, tx, id, &date)
+	if err == sql.ErrNoRows {
+		return ErrAdminNotFound
+	}
+	if err != nil {
+		logger.GetLogger("service-log").Log("disable admin", "error", err.Error())
+		return err
+	}
+
+	err = svc.repo.RevokeAdminRefreshTokens(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("disable admin", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (svc *ServiceImpl) EnableAdmin(ctx context.Context, id uuid.UUID) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("enable admin", "error", err.Error())
+		return err
+	}
 
-	"time"
-)
+	defer helper.WithTransaction(tx, &err)
 
-type ServiceImpl struct {
-	repo repository.Repository
-	db   *sql.DB
+	err = svc.repo.UpdateAdminStatus(ctx, tx, id, nil)
+	if err == sql.ErrNoRows {
+		return ErrAdminNotFound
+	}
+	if err != nil {
+		logger.GetLogger("service-log").Log("enable admin", "error", err.Error())
+		return err
+	}
+
+	return nil
 }
 
-func NewServiceImpl(repo repository.Repository, db *sql.DB) Service {
-	return &ServiceImpl{
-		repo: repo,
-		db:   db,
+func (svc *ServiceImpl) DeleteAdmin(ctx context.Context, id uuid.UUID) (err error) {
+	if err := svc.ensureNotSelf(ctx, id); err != nil {
+		return err
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete admin", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	err = svc.repo.DeleteAdmin(ctx, tx, id)
+	if err == sql.ErrNoRows {
+		return ErrAdminNotFound
+	}
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete admin", "error", err.Error())
+		return err
 	}
+
+	return nil
 }
 
-func (svc *ServiceImpl) Login(ctx context.Context, request *domain.Admin) (*web.AdminResponse, error) {
+func (svc *ServiceImpl) ChangePassword(ctx context.Context, request *web.ChangePasswordRequest) error {
+	admin, err := svc.currentAdmin(ctx)
+	if err != nil {
+		return err
+	}
 
-	result, err := svc.repo.Login(ctx, svc.db, request)
+	if err := helper.ComparePassword(admin.Password, request.CurrentPassword); err != nil {
+		return ErrIncorrectPassword
+	}
+
+	return svc.setPassword(ctx, admin.Id, request.NewPassword)
+}
+
+func (svc *ServiceImpl) ResetAdminPassword(ctx context.Context, id uuid.UUID, request *web.ResetPasswordRequest) error {
+	return svc.setPassword(ctx, id, request.NewPassword)
+}
+
+// setPassword also revokes the admin's refresh tokens so every other session
+// has to log in again with the new password.
+func (svc *ServiceImpl) setPassword(ctx context.Context, id uuid.UUID, password string) (err error) {
+	hash, err := helper.HashPassword(password)
+	if err != nil {
+		logger.GetLogger("service-log").Log("set password", "error", err.Error())
+		return err
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("set password", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	err = svc.repo.UpdateAdminPassword(ctx, tx, id, hash)
+	if err == sql.ErrNoRows {
+		return ErrAdminNotFound
+	}
+	if err != nil {
+		logger.GetLogger("service-log").Log("set password", "error", err.Error())
+		return err
+	}
+
+	err = svc.repo.RevokeAdminRefreshTokens(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("set password", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (svc *ServiceImpl) ensureNotSelf(ctx context.Context, id uuid.UUID) error {
+	claims, ok := helper.ClaimsFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if claims.Subject == id.String() {
+		return ErrCannotModifySelf
+	}
+
+	return nil
+}
+
+func (svc *ServiceImpl) issueTokens(ctx context.Context, tx *sql.Tx, admin *domain.Admin, familyId string) (*web.AdminResponse, error) {
+	accessToken, err := helper.GenerateAccessToken(admin.Id, admin.Username, admin.Role)
 	if err != nil {
 		return nil, err
 	}
 
-	response := &web.AdminResponse{
-		Username: result.Username,
+	refreshToken, tokenHash, err := helper.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	err = svc.repo.AddRefreshToken(ctx, tx, &domain.RefreshToken{
+		Id:        uuid.NewString(),
+		FamilyId:  familyId,
+		AdminId:   admin.Id,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(helper.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &web.AdminResponse{
+		Username:     admin.Username,
+		Role:         admin.Role,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (svc *ServiceImpl) rotateRefreshToken(ctx context.Context, stored *domain.RefreshToken, admin *domain.Admin) (response *web.AdminResponse, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	err = svc.repo.RevokeRefreshToken(ctx, tx, stored.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.issueTokens(ctx, tx, admin, stored.FamilyId)
+}
+
+// revokeReusedFamily kills every token descended from the same login once a
+// rotated refresh token shows up again, since either holder may be an attacker.
+func (svc *ServiceImpl) revokeReusedFamily(ctx context.Context, stored *domain.RefreshToken) (err error) {
+	logger.GetLogger("service-log").Log("refresh token", "warn", "refresh token reuse detected, revoking family "+stored.FamilyId)
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("refresh token", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+
+	err = svc.repo.RevokeRefreshTokenFamily(ctx, tx, stored.FamilyId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("refresh token", "error", err.Error())
+		return err
+	}
+
+	return nil
 }
 
-func (svc *ServiceImpl) AddProduct(ctx context.Context, request *web.Request) (data *domain.Domain, err error) {
+func (svc *ServiceImpl) AddProduct(ctx context.Context, request *web.Request, images []*multipart.FileHeader) (data *domain.Domain, err error) {
+	// Images are processed before the transaction so the sequence row lock
+	// is not held while they are decoded, resized and written.
+	stored, err := svc.storeImages(ctx, images)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add product", "error", err.Error())
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			svc.deleteImageFiles(ctx, stored)
+		}
+	}()
+
 	tx, err := svc.db.Begin()
 	if err != nil {
 		logger.GetLogger("service-log").Log("add product", "error", err.Error())
@@ -50,28 +694,156 @@ func (svc *ServiceImpl) AddProduct(ctx context.Context, request *web.Request) (d
 	request.CreatedAt = &date
 	defer helper.WithTransaction(tx, &err)
 
-	data, err = svc.repo.AddProduct(ctx, tx, (*domain.Domain)(request))
+	seq, err := svc.repo.NextSequenceValue(ctx, tx, productSequence)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add product", "error", err.Error())
+		return nil, err
+	}
+	request.Id = fmt.Sprintf("%s%03d", productIdPrefix, seq)
+
+	data, err = svc.repo.AddProduct(ctx, tx, &domain.Domain{
+		Id:          request.Id,
+		Name:        request.Name,
+		Description: request.Description,
+		Stock:       request.Stock,
+		Price:       request.Price,
+		CategoryId:  request.CategoryId,
+		CreatedAt:   request.CreatedAt,
+		ModifiedAt:  request.ModifiedAt,
+	})
 	if err != nil {
 		logger.GetLogger("service-log").Log("add product", "error", err.Error())
 		return nil, err
 	}
+	if data.Stock != 0 {
+		err = svc.repo.AddStockMovement(ctx, tx, &domain.StockMovement{
+			ProductId:  data.Id,
+			Kind:       domain.StockInitial,
+			Delta:      data.Stock,
+			StockAfter: data.Stock,
+			CreatedAt:  request.CreatedAt,
+		})
+		if err != nil {
+			logger.GetLogger("service-log").Log("add product", "error", err.Error())
+			return nil, err
+		}
+	}
+
+	if err = svc.addImages(ctx, tx, data.Id, stored); err != nil {
+		logger.GetLogger("service-log").Log("add product", "error", err.Error())
+		return nil, err
+	}
+	data.Images = []*domain.ProductImage{}
+	for i, image := range stored {
+		image.Position = i
+		image.URL = svc.store.URL(image.Key)
+		image.ThumbnailURL = svc.store.URL(image.ThumbnailKey)
+		data.Images = append(data.Images, image)
+	}
 
 	return data, nil
 
 }
 
-func (svc *ServiceImpl) GetProducts(ctx context.Context) (data []*domain.Domain, err error) {
+func (svc *ServiceImpl) GetProduct(ctx context.Context, id string) (*web.ProductDetailResponse, error) {
+	product, err := svc.repo.GetProduct(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get product", "error", err.Error())
+		return nil, err
+	}
+	if product.DeletedAt != nil {
+		return nil, ErrProductNotFound
+	}
+	if err := svc.attachImages(ctx, product); err != nil {
+		logger.GetLogger("service-log").Log("get product", "error", err.Error())
+		return nil, err
+	}
+
+	variants, err := svc.repo.GetProductVariants(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get product", "error", err.Error())
+		return nil, err
+	}
+	if variants == nil {
+		variants = []*domain.ProductVariant{}
+	}
+	groups, err := svc.repo.GetOptionGroups(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get product", "error", err.Error())
+		return nil, err
+	}
+	if groups == nil {
+		groups = []*domain.OptionGroup{}
+	}
 
-	products, err := svc.repo.GetProducts(ctx, svc.db)
+	orders, err := svc.repo.GetOrders(ctx, svc.db, &domain.OrderFilter{ProductId: id, Limit: recentOrdersLimit})
 	if err != nil {
 		logger.GetLogger("service-log").Log("get product", "error", err.Error())
 		return nil, err
 	}
+	if orders == nil {
+		orders = []*domain.Orders{}
+	}
+	if err := svc.attachOrderOptions(ctx, orders...); err != nil {
+		logger.GetLogger("service-log").Log("get product", "error", err.Error())
+		return nil, err
+	}
+	allowNextStatuses(orders...)
+
+	return &web.ProductDetailResponse{Domain: product, Variants: variants, OptionGroups: groups, RecentOrders: orders}, nil
+}
+
+func (svc *ServiceImpl) GetProducts(ctx context.Context, filter *domain.ProductFilter) (data []*domain.Domain, meta *web.Meta, err error) {
+	total, err := svc.repo.CountProducts(ctx, svc.db, filter)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get product", "error", err.Error())
+		return nil, nil, err
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	query := *filter
+	query.Limit = filter.Limit + 1
+	products, err := svc.repo.GetProducts(ctx, svc.db, &query)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get product", "error", err.Error())
+		return nil, nil, err
+	}
+
+	meta = &web.Meta{Total: total, PageSize: filter.Limit}
+	if filter.After == nil {
+		meta.Page = filter.Offset/filter.Limit + 1
+	}
+	if len(products) > filter.Limit {
+		products = products[:filter.Limit]
+		meta.NextCursor = helper.EncodeCursor(productCursor(products[len(products)-1], filter.SortBy))
+	}
+
+	if err := svc.attachImages(ctx, products...); err != nil {
+		logger.GetLogger("service-log").Log("get product", "error", err.Error())
+		return nil, nil, err
+	}
 
-	return products, nil
+	return products, meta, nil
 }
 
-func (svc *ServiceImpl) DeleteProduct(ctx context.Context, id string) error {
+func productCursor(product *domain.Domain, sortBy string) domain.Cursor {
+	cursor := domain.Cursor{Id: product.Id}
+	switch sortBy {
+	case "price":
+		cursor.Value = strconv.Itoa(product.Price)
+	case "stock":
+		cursor.Value = strconv.Itoa(product.Stock)
+	case "created_at":
+		if product.CreatedAt != nil {
+			cursor.Value = product.CreatedAt.Format(time.RFC3339Nano)
+		}
+	default:
+		cursor.Value = product.Name
+	}
+	return cursor
+}
+
+func (svc *ServiceImpl) DeleteProduct(ctx context.Context, id string, version int) error {
 	tx, err := svc.db.Begin()
 	if err != nil {
 		logger.GetLogger("service-log").Log("delete product", "error", err.Error())
@@ -80,7 +852,7 @@ func (svc *ServiceImpl) DeleteProduct(ctx context.Context, id string) error {
 
 	defer helper.WithTransaction(tx, &err)
 
-	err = svc.repo.DeleteProduct(ctx, tx, id)
+	err = svc.repo.DeleteProduct(ctx, tx, id, version)
 	if err != nil {
 		logger.GetLogger("service-log").Log("delete product", "error", err.Error())
 		return err
@@ -89,7 +861,30 @@ func (svc *ServiceImpl) DeleteProduct(ctx context.Context, id string) error {
 	return nil
 }
 
-func (svc *ServiceImpl) UpdateProduct(ctx context.Context, request *web.Request, id string) (data *domain.Domain, err error) {
+// UpdateProduct applies the patch and appends any uploaded images.
+func (svc *ServiceImpl) UpdateProduct(ctx context.Context, request *web.UpdateProductRequest, images []*multipart.FileHeader, id string, version int) (*domain.Domain, error) {
+	stored, err := svc.storeImages(ctx, images)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update product", "error", err.Error())
+		return nil, err
+	}
+
+	data, err := svc.updateProduct(ctx, request, stored, id, version)
+	if err != nil {
+		svc.deleteImageFiles(ctx, stored)
+		return nil, err
+	}
+
+	// Read the images back after commit to report their final positions.
+	if err := svc.attachImages(ctx, data); err != nil {
+		logger.GetLogger("service-log").Log("update product", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) updateProduct(ctx context.Context, request *web.UpdateProductRequest, images []*domain.ProductImage, id string, version int) (data *domain.Domain, err error) {
 	tx, err := svc.db.Begin()
 	if err != nil {
 		logger.GetLogger("service-log").Log("update product", "error", err.Error())
@@ -97,29 +892,152 @@ func (svc *ServiceImpl) UpdateProduct(ctx context.Context, request *web.Request,
 	}
 
 	date := time.Now()
-	request.ModifiedAt = &date
+	patch := request.ToPatch()
+	patch.ModifiedAt = &date
 	defer helper.WithTransaction(tx, &err)
-	data, err = svc.repo.UpdateProduct(ctx, tx, (*domain.Domain)(request), id)
+	data, err = svc.repo.UpdateProduct(ctx, tx, patch, id, version)
 	if err != nil {
 		logger.GetLogger("service-log").Log("update product", "error", err.Error())
 		return nil, err
 	}
+	if request.Stock != nil {
+		if err = svc.setStock(ctx, tx, data, *request.Stock, request.StockReason); err != nil {
+			logger.GetLogger("service-log").Log("update product", "error", err.Error())
+			return nil, err
+		}
+	}
+
+	if err = svc.addImages(ctx, tx, id, images); err != nil {
+		logger.GetLogger("service-log").Log("update product", "error", err.Error())
+		return nil, err
+	}
 
 	return data, nil
 }
 
-func (svc *ServiceImpl) GetOrders(ctx context.Context) (orders []*domain.Orders, err error) {
+func (svc *ServiceImpl) RestoreProduct(ctx context.Context, id string) (data *domain.Domain, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("restore product", "error", err.Error())
+		return nil, err
+	}
 
-	orders, err = svc.repo.GetOrders(ctx, svc.db)
+	defer helper.WithTransaction(tx, &err)
+	data, err = svc.repo.RestoreProduct(ctx, tx, id)
 	if err != nil {
-		logger.GetLogger("service-log").Log("get orders", "error", err.Error())
+		logger.GetLogger("service-log").Log("restore product", "error", err.Error())
+		return nil, err
+	}
+	if err = svc.attachImages(ctx, data); err != nil {
+		logger.GetLogger("service-log").Log("restore product", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) PurgeDeletedProducts(ctx context.Context, retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+	images, err := svc.repo.GetPurgeableProductImages(ctx, svc.db, cutoff)
+	if err != nil {
+		logger.GetLogger("service-log").Log("purge products", "error", err.Error())
+		return 0, err
+	}
+
+	purged, err := svc.repo.PurgeDeletedProducts(ctx, svc.db, cutoff)
+	if err != nil {
+		logger.GetLogger("service-log").Log("purge products", "error", err.Error())
+		return 0, err
+	}
+
+	svc.deleteImageFiles(ctx, images)
+	return purged, nil
+}
+
+func (svc *ServiceImpl) GetOrder(ctx context.Context, id string) (*web.OrderDetailResponse, error) {
+	order, err := svc.repo.GetOrder(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get order", "error", err.Error())
+		return nil, err
+	}
+
+	response := &web.OrderDetailResponse{Orders: order}
+	if order.PackageId != nil {
+		response.Package, err = svc.repo.GetPackage(ctx, svc.db, *order.PackageId)
+		if err != nil {
+			logger.GetLogger("service-log").Log("get order", "error", err.Error())
+			return nil, err
+		}
+		priceBundle(response.Package)
+	} else {
+		product, err := svc.repo.GetProduct(ctx, svc.db, order.ProductId)
+		if err != nil && !errors.Is(err, ErrProductNotFound) {
+			logger.GetLogger("service-log").Log("get order", "error", err.Error())
+			return nil, err
+		}
+
+		if product != nil {
+			if err := svc.attachImages(ctx, product); err != nil {
+				logger.GetLogger("service-log").Log("get order", "error", err.Error())
+				return nil, err
+			}
+		}
+		response.Product = product
+	}
+	if err := svc.attachOrderOptions(ctx, order); err != nil {
+		logger.GetLogger("service-log").Log("get order", "error", err.Error())
 		return nil, err
 	}
+	allowNextStatuses(order)
+
+	response.StatusHistory, err = svc.repo.GetOrderStatusHistory(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get order", "error", err.Error())
+		return nil, err
+	}
+	if response.StatusHistory == nil {
+		response.StatusHistory = []*domain.OrderStatusChange{}
+	}
+
+	return response, nil
+}
+
+func (svc *ServiceImpl) GetOrders(ctx context.Context, filter *domain.OrderFilter) (orders []*domain.Orders, meta *web.Meta, err error) {
+	total, err := svc.repo.CountOrders(ctx, svc.db, filter)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get orders", "error", err.Error())
+		return nil, nil, err
+	}
+
+	query := *filter
+	query.Limit = filter.Limit + 1
+	orders, err = svc.repo.GetOrders(ctx, svc.db, &query)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get orders", "error", err.Error())
+		return nil, nil, err
+	}
 
-	return orders, nil
+	meta = &web.Meta{Total: total, PageSize: filter.Limit}
+	if len(orders) > filter.Limit {
+		orders = orders[:filter.Limit]
+		last := orders[len(orders)-1]
+		cursor := domain.Cursor{Id: last.Id}
+		if last.CreatedAt != nil {
+			cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+		}
+		meta.NextCursor = helper.EncodeCursor(cursor)
+	}
+
+	if err := svc.attachOrderOptions(ctx, orders...); err != nil {
+		logger.GetLogger("service-log").Log("get orders", "error", err.Error())
+		return nil, nil, err
+	}
+	allowNextStatuses(orders...)
+
+	return orders, meta, nil
 }
 
-func (svc *ServiceImpl) UpdateOrder(ctx context.Context, entity *domain.Orders, id string) (err error) {
+func (svc *ServiceImpl) UpdateOrder(ctx context.Context, entity *domain.Orders, id string, version int) (err error) {
 	tx, err := svc.db.Begin()
 	if err != nil {
 		logger.GetLogger("service-log").Log("update order", "error", err.Error())
@@ -128,7 +1046,44 @@ func (svc *ServiceImpl) UpdateOrder(ctx context.Context, entity *domain.Orders,
 
 	defer helper.WithTransaction(tx, &err)
 
-	err = svc.repo.UpdateOrder(ctx, tx, entity, id)
+	order, err := svc.repo.GetOrderForUpdate(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update order", "error", err.Error())
+		return err
+	}
+	// A stale client is told to reload before being told the move is illegal.
+	if order.Version != version {
+		return domain.NewError(domain.KindStale, "order", nil)
+	}
+	if err = checkOrderTransition(order.Status, entity.Status); err != nil {
+		return err
+	}
+
+	// Confirming reserves the order's stock and cancelling gives it back;
+	// stock_reserved keeps either from happening twice. Once the order is
+	// completed its stock is used up and nothing is held any more.
+	reserve := holdsStock(entity.Status) && !order.StockReserved
+	release := entity.Status == orderStatusCancelled && order.StockReserved
+	entity.StockReserved = holdsStock(entity.Status)
+
+	err = svc.repo.UpdateOrder(ctx, tx, entity, id, version)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update order", "error", err.Error())
+		return err
+	}
+
+	date := time.Now()
+	change := &domain.OrderStatusChange{OrderId: id, FromStatus: order.Status, ToStatus: entity.Status, Note: entity.Note, CreatedAt: &date}
+	if err = svc.repo.AddOrderStatusChange(ctx, tx, change); err != nil {
+		logger.GetLogger("service-log").Log("update order", "error", err.Error())
+		return err
+	}
+
+	if reserve {
+		err = svc.reserveOrderStock(ctx, tx, id)
+	} else if release {
+		err = svc.releaseOrderStock(ctx, tx, id, "Order cancelled")
+	}
 	if err != nil {
 		logger.GetLogger("service-log").Log("update order", "error", err.Error())
 		return err
@@ -137,7 +1092,9 @@ func (svc *ServiceImpl) UpdateOrder(ctx context.Context, entity *domain.Orders,
 	return nil
 }
 
-func (svc *ServiceImpl) DeleteOrder(ctx context.Context, id string) error {
+// DeleteOrder gives back any stock the order still holds before removing it;
+// a completed or refunded order has used its stock up.
+func (svc *ServiceImpl) DeleteOrder(ctx context.Context, id string, version int) (err error) {
 	tx, err := svc.db.Begin()
 	if err != nil {
 		logger.GetLogger("service-log").Log("delete order", "error", err.Error())
@@ -146,7 +1103,22 @@ func (svc *ServiceImpl) DeleteOrder(ctx context.Context, id string) error {
 
 	defer helper.WithTransaction(tx, &err)
 
-	err = svc.repo.DeleteOrder(ctx, tx, id)
+	order, err := svc.repo.GetOrderForUpdate(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete order", "error", err.Error())
+		return err
+	}
+	if order.Version != version {
+		return domain.NewError(domain.KindStale, "order", nil)
+	}
+	if order.StockReserved && holdsStock(order.Status) {
+		if err = svc.releaseOrderStock(ctx, tx, id, "Order deleted"); err != nil {
+			logger.GetLogger("service-log").Log("delete order", "error", err.Error())
+			return err
+		}
+	}
+
+	err = svc.repo.DeleteOrder(ctx, tx, id, version)
 	if err != nil {
 		logger.GetLogger("service-log").Log("delete order", "error", err.Error())
 		return err
@@ -1,107 +1,211 @@
 package service
 
 import (
+	"bytes"
 	"catering-admin-go/domain"
+	"catering-admin-go/helper"
 	"catering-admin-go/repository/mocks"
+	"catering-admin-go/storage"
 	"catering-admin-go/web"
 	"context"
+	"database/sql"
 	"errors"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestGetProducts(t *testing.T) {
+	products := []*domain.Domain{
+		{Id: "PRD001", Name: "Product 1", Price: 100, Stock: 10},
+		{Id: "PRD002", Name: "Product 2", Price: 200, Stock: 10},
+		{Id: "PRD003", Name: "Product 3", Price: 300, Stock: 10},
+	}
+
 	tests := []struct {
-		name           string
-		setupMock      func(sqlmock.Sqlmock, *mocks.Repository)
-		expectedErr    bool
-		expectedResult []*domain.Domain
-		checkResult    func(t *testing.T, result []*domain.Domain, err error)
+		name        string
+		filter      *domain.ProductFilter
+		setupMock   func(repo *mocks.Repository)
+		checkResult func(t *testing.T, result []*domain.Domain, meta *web.Meta, err error)
 	}{
 		{
-			name: "Success",
-			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
-				response := []*domain.Domain{
-					{
-						Id:          "P001",
-						Name:        "Product 1",
-						Description: "1st Product",
-						Price:       100,
-						Stock:       10,
-					},
-					{
-						Id:          "P001",
-						Name:        "Product 2",
-						Description: "2nd Product",
-						Price:       100,
-						Stock:       10,
-					},
-				}
-
-				repo.On("GetProducts", mock.Anything, mock.Anything).Return(response, nil)
-
+			name:   "Last page",
+			filter: &domain.ProductFilter{SortBy: "name", Limit: 20},
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("CountProducts", mock.Anything, mock.Anything, mock.Anything).Return(2, nil)
+				repo.On("GetProducts", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *domain.ProductFilter) bool {
+					return filter.Limit == 21
+				})).Return(products[:2], nil)
+				repo.On("GetProductImages", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 			},
-			expectedErr: false,
-			checkResult: func(t *testing.T, result []*domain.Domain, err error) {
+			checkResult: func(t *testing.T, result []*domain.Domain, meta *web.Meta, err error) {
 				assert.NoError(t, err)
 				assert.Len(t, result, 2)
-				assert.Equal(t, "Product 1", result[0].Name)
-				assert.Equal(t, "Product 2", result[1].Name)
+				assert.Equal(t, &web.Meta{Total: 2, Page: 1, PageSize: 20}, meta)
 			},
 		},
 		{
-			name: "Failed",
-			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+			name:   "More pages follow",
+			filter: &domain.ProductFilter{SortBy: "price", Limit: 2, Offset: 2},
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("CountProducts", mock.Anything, mock.Anything, mock.Anything).Return(5, nil)
+				repo.On("GetProducts", mock.Anything, mock.Anything, mock.Anything).Return(products, nil)
+				repo.On("GetProductImages", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			},
+			checkResult: func(t *testing.T, result []*domain.Domain, meta *web.Meta, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, result, 2)
+				assert.Equal(t, 2, meta.Page)
+				assert.Equal(t, 5, meta.Total)
 
-				repo.On("GetProducts", mock.Anything, mock.Anything).Return(nil, errors.New("cannot get products"))
+				cursor, err := helper.DecodeCursor(meta.NextCursor)
+				assert.NoError(t, err)
+				assert.Equal(t, &domain.Cursor{Value: "200", Id: "PRD002"}, cursor)
 			},
-			expectedErr: true,
-			checkResult: func(t *testing.T, result []*domain.Domain, err error) {
+		},
+		{
+			name:   "Count failed",
+			filter: &domain.ProductFilter{SortBy: "name", Limit: 20},
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("CountProducts", mock.Anything, mock.Anything, mock.Anything).Return(0, errors.New("cannot count products"))
+			},
+			checkResult: func(t *testing.T, result []*domain.Domain, meta *web.Meta, err error) {
 				assert.Error(t, err)
 				assert.Nil(t, result)
+				assert.Nil(t, meta)
 			},
 		},
 		{
-			name: "NotFound",
-			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
-				response := []*domain.Domain{}
+			name:   "Failed",
+			filter: &domain.ProductFilter{SortBy: "name", Limit: 20},
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("CountProducts", mock.Anything, mock.Anything, mock.Anything).Return(3, nil)
+				repo.On("GetProducts", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("cannot get products"))
+			},
+			checkResult: func(t *testing.T, result []*domain.Domain, meta *web.Meta, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbmock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(repo)
+
+			svc := NewServiceImpl(repo, db, nil)
+			products, meta, err := svc.GetProducts(context.Background(), tt.filter)
+
+			tt.checkResult(t, products, meta, err)
+			assert.NoError(t, dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetOrders(t *testing.T) {
+	createdAt := time.Date(2025, 1, 15, 8, 30, 0, 0, time.UTC)
+	orders := []*domain.Orders{
+		{Id: "order-2", Status: "pending", CreatedAt: &createdAt},
+		{Id: "order-1", Status: "pending", CreatedAt: &createdAt},
+	}
+
+	db, dbmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
 
-				repo.On("GetProducts", mock.Anything, mock.Anything).Return(response, nil)
+	repo := mocks.NewRepository(t)
+	repo.On("CountOrders", mock.Anything, mock.Anything, mock.Anything).Return(3, nil)
+	repo.On("GetOrders", mock.Anything, mock.Anything, mock.MatchedBy(func(filter *domain.OrderFilter) bool {
+		return filter.Status == "pending" && filter.Limit == 2
+	})).Return(orders, nil)
+	repo.On("GetOrderOptions", mock.Anything, mock.Anything, []string{"order-2"}).Return([]*domain.OrderOption{{OrderId: "order-2", GroupName: "Spice level", Name: "Extra hot"}}, nil)
 
+	svc := NewServiceImpl(repo, db, nil)
+	result, meta, err := svc.GetOrders(context.Background(), &domain.OrderFilter{Status: "pending", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Len(t, result[0].Options, 1)
+	assert.Equal(t, []string{"confirmed", "cancelled"}, result[0].AllowedNextStatuses)
+	assert.Equal(t, 3, meta.Total)
+	assert.Equal(t, 1, meta.PageSize)
+
+	cursor, err := helper.DecodeCursor(meta.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "order-2", cursor.Id)
+	assert.Equal(t, createdAt.Format(time.RFC3339Nano), cursor.Value)
+
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestGetProduct(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(repo *mocks.Repository)
+		expectedErr error
+		checkResult func(t *testing.T, result *web.ProductDetailResponse)
+	}{
+		{
+			name: "Includes recent orders",
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("GetProduct", mock.Anything, mock.Anything, "PRD001").Return(&domain.Domain{Id: "PRD001", Name: "Product 1"}, nil)
+				repo.On("GetOrders", mock.Anything, mock.Anything, &domain.OrderFilter{ProductId: "PRD001", Limit: 5}).Return([]*domain.Orders{{Id: "order-1", ProductId: "PRD001"}}, nil)
+				repo.On("GetOrderOptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				repo.On("GetProductVariants", mock.Anything, mock.Anything, "PRD001").Return(nil, nil)
+				repo.On("GetOptionGroups", mock.Anything, mock.Anything, "PRD001").Return(nil, nil)
+				repo.On("GetProductImages", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 			},
-			expectedErr: false,
-			checkResult: func(t *testing.T, result []*domain.Domain, err error) {
-				assert.NoError(t, err)
-				assert.Empty(t, result)
+			checkResult: func(t *testing.T, result *web.ProductDetailResponse) {
+				assert.Equal(t, "Product 1", result.Name)
+				assert.Len(t, result.RecentOrders, 1)
 			},
 		},
 		{
-			name: "ErrTransactionCommit",
-			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
-
-				repo.On("GetProducts", mock.Anything, mock.Anything).Return(nil, errors.New("repository error"))
+			name: "No orders yet",
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("GetProduct", mock.Anything, mock.Anything, "PRD001").Return(&domain.Domain{Id: "PRD001"}, nil)
+				repo.On("GetOrders", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				repo.On("GetProductVariants", mock.Anything, mock.Anything, "PRD001").Return(nil, nil)
+				repo.On("GetOptionGroups", mock.Anything, mock.Anything, "PRD001").Return(nil, nil)
+				repo.On("GetProductImages", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 			},
-			expectedErr: true,
-			checkResult: func(t *testing.T, result []*domain.Domain, err error) {
-				assert.Error(t, err)
-				assert.Nil(t, result)
+			checkResult: func(t *testing.T, result *web.ProductDetailResponse) {
+				assert.NotNil(t, result.RecentOrders)
+				assert.Empty(t, result.RecentOrders)
 			},
 		},
 		{
-			name: "ErrTransactionRollback",
-			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
-
-				repo.On("GetProducts", mock.Anything, mock.Anything).Return(nil, errors.New("repository error"))
+			name: "Not found",
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("GetProduct", mock.Anything, mock.Anything, "PRD001").Return(nil, domain.NewError(domain.KindNotFound, "product", sql.ErrNoRows))
 			},
-			expectedErr: true,
-			checkResult: func(t *testing.T, result []*domain.Domain, err error) {
-				assert.Error(t, err)
-				assert.Nil(t, result)
+			expectedErr: ErrProductNotFound,
+		},
+		{
+			name: "In the trash",
+			setupMock: func(repo *mocks.Repository) {
+				deletedAt := time.Now()
+				repo.On("GetProduct", mock.Anything, mock.Anything, "PRD001").Return(&domain.Domain{Id: "PRD001", DeletedAt: &deletedAt}, nil)
 			},
+			expectedErr: ErrProductNotFound,
 		},
 	}
 
@@ -113,21 +217,103 @@ func TestGetProducts(t *testing.T) {
 			}
 			defer db.Close()
 
-			var repo *mocks.Repository
-			if tt.name != "ErrTransaction" {
-				repo = mocks.NewRepository(t)
+			repo := mocks.NewRepository(t)
+			tt.setupMock(repo)
+
+			svc := NewServiceImpl(repo, db, nil)
+			result, err := svc.GetProduct(context.Background(), "PRD001")
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.checkResult != nil {
+				tt.checkResult(t, result)
 			}
 
-			tt.setupMock(dbmock, repo)
+			assert.NoError(t, dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	tests := []struct {
+		name            string
+		setupMock       func(repo *mocks.Repository)
+		expectedErr     error
+		expectedProduct bool
+		expectedPackage bool
+		expectedHistory int
+	}{
+		{
+			name: "Includes product",
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("GetOrder", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", ProductId: "PRD001"}, nil)
+				repo.On("GetProduct", mock.Anything, mock.Anything, "PRD001").Return(&domain.Domain{Id: "PRD001"}, nil)
+				repo.On("GetProductImages", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				repo.On("GetOrderOptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				repo.On("GetOrderStatusHistory", mock.Anything, mock.Anything, "order-1").Return([]*domain.OrderStatusChange{
+					{Id: 1, OrderId: "order-1", FromStatus: "pending", ToStatus: "confirmed"},
+				}, nil)
+			},
+			expectedProduct: true,
+			expectedHistory: 1,
+		},
+		{
+			name: "Product no longer exists",
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("GetOrder", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", ProductId: "PRD001"}, nil)
+				repo.On("GetProduct", mock.Anything, mock.Anything, "PRD001").Return(nil, domain.NewError(domain.KindNotFound, "product", sql.ErrNoRows))
+				repo.On("GetOrderOptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				repo.On("GetOrderStatusHistory", mock.Anything, mock.Anything, "order-1").Return(nil, nil)
+			},
+			expectedProduct: false,
+		},
+		{
+			name: "Includes package",
+			setupMock: func(repo *mocks.Repository) {
+				packageId := "PKG001"
+				repo.On("GetOrder", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", PackageId: &packageId}, nil)
+				repo.On("GetPackage", mock.Anything, mock.Anything, "PKG001").Return(&domain.Package{Id: "PKG001", Pricing: domain.PackagePricingFixed}, nil)
+				repo.On("GetOrderOptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+				repo.On("GetOrderStatusHistory", mock.Anything, mock.Anything, "order-1").Return(nil, nil)
+			},
+			expectedPackage: true,
+		},
+		{
+			name: "Not found",
+			setupMock: func(repo *mocks.Repository) {
+				repo.On("GetOrder", mock.Anything, mock.Anything, "order-1").Return(nil, domain.NewError(domain.KindNotFound, "order", sql.ErrNoRows))
+			},
+			expectedErr: ErrOrderNotFound,
+		},
+	}
 
-			svc := NewServiceImpl(repo, db)
-			products, err := svc.GetProducts(context.Background())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbmock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
 
-			tt.checkResult(t, products, err)
+			repo := mocks.NewRepository(t)
+			tt.setupMock(repo)
 
-			if repo != nil {
-				repo.AssertExpectations(t)
+			svc := NewServiceImpl(repo, db, nil)
+			result, err := svc.GetOrder(context.Background(), "order-1")
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
 			}
+			if tt.expectedErr == nil {
+				assert.Equal(t, "order-1", result.Id)
+				assert.Equal(t, tt.expectedProduct, result.Product != nil)
+				assert.Equal(t, tt.expectedPackage, result.Package != nil)
+				assert.Len(t, result.StatusHistory, tt.expectedHistory)
+			}
+
 			assert.NoError(t, dbmock.ExpectationsWereMet())
 		})
 	}
@@ -152,7 +338,13 @@ func TestAddProduct(t *testing.T) {
 				}
 
 				dbmock.ExpectBegin()
-				repo.On("AddProduct", mock.Anything, mock.Anything, mock.Anything).Return(response, nil)
+				repo.On("NextSequenceValue", mock.Anything, mock.Anything, "products").Return(int64(7), nil)
+				repo.On("AddProduct", mock.Anything, mock.Anything, mock.MatchedBy(func(product *domain.Domain) bool {
+					return product.Id == "PRD007"
+				})).Return(response, nil)
+				repo.On("AddStockMovement", mock.Anything, mock.Anything, mock.MatchedBy(func(m *domain.StockMovement) bool {
+					return m.Kind == domain.StockInitial && m.Delta == 10 && m.StockAfter == 10
+				})).Return(nil)
 				dbmock.ExpectCommit()
 			},
 			expectedErr: false,
@@ -167,12 +359,23 @@ func TestAddProduct(t *testing.T) {
 			name: "Failed",
 			mockSetup: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				dbmock.ExpectBegin()
+				repo.On("NextSequenceValue", mock.Anything, mock.Anything, "products").Return(int64(8), nil)
 				repo.On("AddProduct", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("field cannot be empty"))
 				dbmock.ExpectRollback()
 			},
 			expectedErr:    true,
 			expectedResult: nil,
 		},
+		{
+			name: "Sequence failed",
+			mockSetup: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("NextSequenceValue", mock.Anything, mock.Anything, "products").Return(int64(0), errors.New("lock wait timeout"))
+				dbmock.ExpectRollback()
+			},
+			expectedErr:    true,
+			expectedResult: nil,
+		},
 		{
 			name: "Transaction Failed",
 			mockSetup: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
@@ -202,14 +405,13 @@ func TestAddProduct(t *testing.T) {
 			repo := mocks.NewRepository(t)
 			tt.mockSetup(mock, repo)
 
-			svc := NewServiceImpl(repo, db)
+			svc := NewServiceImpl(repo, db, nil)
 			result, err := svc.AddProduct(context.Background(), &web.Request{
-				Id:          "P001",
 				Name:        "Product 1",
 				Description: "1st Product",
 				Price:       100,
 				Stock:       10,
-			})
+			}, nil)
 
 			if tt.expectedErr && err == nil {
 				t.Error("expected error, but got none")
@@ -231,6 +433,111 @@ func TestAddProduct(t *testing.T) {
 	}
 }
 
+// uploadFile builds the file header a multipart request for content would
+// carry.
+func uploadFile(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("images", filename)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(MaxImageSize)
+	assert.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["images"][0]
+}
+
+func TestAddProductImages(t *testing.T) {
+	var pngData bytes.Buffer
+	assert.NoError(t, png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 640, 480))))
+
+	tests := []struct {
+		name          string
+		file          []byte
+		mockSetup     func(dbmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr   error
+		expectedFiles int
+	}{
+		{
+			name: "Stores image and thumbnail",
+			file: pngData.Bytes(),
+			mockSetup: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("NextSequenceValue", mock.Anything, mock.Anything, "products").Return(int64(7), nil)
+				repo.On("AddProduct", mock.Anything, mock.Anything, mock.Anything).Return(&domain.Domain{Id: "PRD007"}, nil)
+				repo.On("AddProductImage", mock.Anything, mock.Anything, mock.MatchedBy(func(image *domain.ProductImage) bool {
+					return image.ProductId == "PRD007" && image.ContentType == "image/png"
+				})).Return(nil)
+				dbmock.ExpectCommit()
+			},
+			expectedFiles: 2,
+		},
+		{
+			name:        "Rejects files that are not images",
+			file:        []byte("<html></html>"),
+			mockSetup:   func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {},
+			expectedErr: ErrInvalidImage,
+		},
+		{
+			name: "Removes files when the transaction cannot start",
+			file: pngData.Bytes(),
+			mockSetup: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin().WillReturnError(errors.New("failed begin"))
+			},
+			expectedErr: errors.New("failed begin"),
+		},
+		{
+			name: "Removes files when the product is not saved",
+			file: pngData.Bytes(),
+			mockSetup: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("NextSequenceValue", mock.Anything, mock.Anything, "products").Return(int64(9), nil)
+				repo.On("AddProduct", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("failed add product"))
+				dbmock.ExpectRollback()
+			},
+			expectedErr: errors.New("failed add product"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbmock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.mockSetup(dbmock, repo)
+
+			root := t.TempDir()
+			store := &storage.LocalStorage{Root: root, URLPrefix: "/uploads"}
+			svc := NewServiceImpl(repo, db, store)
+
+			result, err := svc.AddProduct(context.Background(), &web.Request{Name: "Product 1", Price: 100}, []*multipart.FileHeader{uploadFile(t, "photo.png", tt.file)})
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result.Images, 1)
+				assert.Equal(t, "PRD007", result.Images[0].ProductId)
+				assert.Contains(t, result.Images[0].ThumbnailURL, "/uploads/products/")
+			}
+
+			var files int
+			filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+				if err == nil && !entry.IsDir() {
+					files++
+				}
+				return nil
+			})
+			assert.Equal(t, tt.expectedFiles, files)
+			assert.NoError(t, dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestUpdateProduct(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -242,20 +549,26 @@ func TestUpdateProduct(t *testing.T) {
 			name: "Success",
 			mockSetup: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				response := &domain.Domain{
+					Id:          "PRD001",
 					Name:        "Product 1",
 					Description: "1st Product",
 					Price:       2000,
-					Stock:       100,
+					Stock:       60,
 				}
 				sqlmock.ExpectBegin()
-				repo.On("UpdateProduct", mock.Anything, mock.Anything, mock.MatchedBy(func(p *domain.Domain) bool {
-					return p.Name == "Product 1" &&
-						p.Description == "1st Product" &&
-						p.Price == 2000 &&
-						p.Stock == 100
-				}), mock.Anything).Return(response, nil)
+				repo.On("UpdateProduct", mock.Anything, mock.Anything, mock.MatchedBy(func(p *domain.ProductPatch) bool {
+					return p.Name == nil &&
+						p.Description == nil &&
+						*p.Price == 2000 &&
+						p.ModifiedAt != nil
+				}), mock.Anything, 1).Return(response, nil)
+				repo.On("MoveStock", mock.Anything, mock.Anything, mock.MatchedBy(func(m *domain.StockMovement) bool {
+					return m.ProductId == "PRD001" && m.Kind == domain.StockAdjustment && m.Delta == 40
+				})).Run(func(args mock.Arguments) {
+					args.Get(2).(*domain.StockMovement).StockAfter = 100
+				}).Return(nil)
 				sqlmock.ExpectCommit()
-
+				repo.On("GetProductImages", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 			},
 			expectedErr: false,
 			expectedResult: &domain.Domain{
@@ -263,13 +576,14 @@ func TestUpdateProduct(t *testing.T) {
 				Description: "1st Product",
 				Price:       2000,
 				Stock:       100,
+				Images:      []*domain.ProductImage{},
 			},
 		},
 		{
 			name: "Failed",
 			mockSetup: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				sqlmock.ExpectBegin()
-				repo.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("failed update product"))
+				repo.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1).Return(nil, errors.New("failed update product"))
 				sqlmock.ExpectRollback()
 			},
 			expectedErr:    true,
@@ -298,7 +612,7 @@ func TestUpdateProduct(t *testing.T) {
 			mockSetup: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				sqlmock.ExpectBegin()
 
-				repo.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("id not found"))
+				repo.On("UpdateProduct", mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), 1).Return(nil, errors.New("id not found"))
 				sqlmock.ExpectRollback()
 			},
 			expectedErr:    true,
@@ -319,14 +633,12 @@ func TestUpdateProduct(t *testing.T) {
 
 			id := "8154bf2e-2723-4149-b366-01998b2b3f00"
 
-			svc := NewServiceImpl(repo, db)
-			result, err := svc.UpdateProduct(context.Background(), &web.Request{
-
-				Name:        "Product 1",
-				Description: "1st Product",
-				Price:       2000,
-				Stock:       100,
-			}, id)
+			price, stock := 2000, 100
+			svc := NewServiceImpl(repo, db, nil)
+			result, err := svc.UpdateProduct(context.Background(), &web.UpdateProductRequest{
+				Price: &price,
+				Stock: &stock,
+			}, nil, id, 1)
 
 			if tt.expectedErr && err != nil {
 				assert.Error(t, err, "Success error test")
@@ -348,6 +660,63 @@ func TestUpdateProduct(t *testing.T) {
 	}
 }
 
+func TestAddStockMovement(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *web.StockMovementRequest
+		setupMock   func(dbmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
+	}{
+		{
+			name:    "Waste",
+			request: &web.StockMovementRequest{Kind: domain.StockWaste, Delta: -3, Reason: "Spoiled"},
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("MoveStock", mock.Anything, mock.Anything, mock.MatchedBy(func(m *domain.StockMovement) bool {
+					return m.ProductId == "PRD001" && m.Kind == domain.StockWaste && m.Delta == -3 && m.Reason == "Spoiled"
+				})).Return(nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name:        "Waste that adds stock",
+			request:     &web.StockMovementRequest{Kind: domain.StockWaste, Delta: 3, Reason: "Spoiled"},
+			setupMock:   func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {},
+			expectedErr: ErrInvalidStockMovement,
+		},
+		{
+			name:    "Not enough stock",
+			request: &web.StockMovementRequest{Kind: domain.StockAdjustment, Delta: -300, Reason: "Recount"},
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("MoveStock", mock.Anything, mock.Anything, mock.Anything).Return(&domain.Error{Kind: domain.KindConflict, Entity: "stock"})
+				dbmock.ExpectRollback()
+			},
+			expectedErr: ErrInsufficientStock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbmock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(dbmock, repo)
+
+			movement, err := NewServiceImpl(repo, db, nil).AddStockMovement(context.Background(), "PRD001", tt.request)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, -3, movement.Delta)
+			}
+			assert.NoError(t, dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestDeleteProduct(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -358,7 +727,7 @@ func TestDeleteProduct(t *testing.T) {
 			name: "Success",
 			mockSetup: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				sqlmock.ExpectBegin()
-				repo.On("DeleteProduct", mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return(nil)
+				repo.On("DeleteProduct", mock.Anything, mock.Anything, mock.AnythingOfType("string"), 1).Return(nil)
 				sqlmock.ExpectCommit()
 			},
 			expectedErr: false,
@@ -367,7 +736,7 @@ func TestDeleteProduct(t *testing.T) {
 			name: "Failed",
 			mockSetup: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				sqlmock.ExpectBegin()
-				repo.On("DeleteProduct", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("delete product failed"))
+				repo.On("DeleteProduct", mock.Anything, mock.Anything, mock.Anything, 1).Return(errors.New("delete product failed"))
 				sqlmock.ExpectRollback()
 			},
 			expectedErr: true,
@@ -376,7 +745,7 @@ func TestDeleteProduct(t *testing.T) {
 			name: "Id not found",
 			mockSetup: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				sqlmock.ExpectBegin()
-				repo.On("DeleteProduct", mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return(errors.New("id not found"))
+				repo.On("DeleteProduct", mock.Anything, mock.Anything, mock.AnythingOfType("string"), 1).Return(errors.New("id not found"))
 				sqlmock.ExpectRollback()
 			},
 			expectedErr: true,
@@ -407,8 +776,8 @@ func TestDeleteProduct(t *testing.T) {
 			tt.mockSetup(mock, repo)
 
 			id := uuid.New()
-			svc := NewServiceImpl(repo, db)
-			err = svc.DeleteProduct(context.Background(), id.String())
+			svc := NewServiceImpl(repo, db, nil)
+			err = svc.DeleteProduct(context.Background(), id.String(), 1)
 
 			if tt.expectedErr {
 				assert.Error(t, err, "error happen")
@@ -434,41 +803,74 @@ func TestDeleteProduct(t *testing.T) {
 }
 
 func TestLogin(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
 	id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
 	username := "ADMIN"
+	password := "admin123"
+	hash, err := helper.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []struct {
-		name           string
-		setupMock      func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository)
-		expectedErr    bool
-		expectedResult *web.AdminResponse
+		name        string
+		password    string
+		setupMock   func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
 	}{
 		{
-			name: "Success",
+			name:     "Success",
+			password: password,
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				rows := &domain.Admin{
+					Id:       id,
+					Username: username,
+					Password: hash,
+				}
+				repo.On("Login", mock.Anything, mock.Anything, mock.AnythingOfType("*domain.Admin")).Return(rows, nil)
+				sqlmock.ExpectBegin()
+				repo.On("AddRefreshToken", mock.Anything, mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+					return token.AdminId == id && token.FamilyId != "" && len(token.TokenHash) == 64
+				})).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "Wrong password",
+			password: "wrong-password",
 			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				rows := &domain.Admin{
 					Id:       id,
 					Username: username,
+					Password: hash,
 				}
 				repo.On("Login", mock.Anything, mock.Anything, mock.AnythingOfType("*domain.Admin")).Return(rows, nil)
 			},
-			expectedErr: false,
-			expectedResult: &web.AdminResponse{
-				Username: username,
+			expectedErr: ErrInvalidCredentials,
+		},
+		{
+			name:     "Unknown username",
+			password: password,
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				repo.On("Login", mock.Anything, mock.Anything, mock.AnythingOfType("*domain.Admin")).Return(nil, sql.ErrNoRows)
 			},
+			expectedErr: ErrInvalidCredentials,
 		},
 		{
-			name: "Failed",
+			name:     "Failed",
+			password: password,
 			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
 				repo.On("Login", mock.Anything, mock.Anything, mock.AnythingOfType("*domain.Admin")).Return(nil, errors.New("failed"))
 			},
-			expectedErr:    true,
-			expectedResult: nil,
+			expectedErr: errors.New("failed"),
 		},
 	}
 	for _, tt := range tests {
 		loginRequest := &domain.Admin{
-			Id:       id,
 			Username: username,
+			Password: tt.password,
 		}
 		t.Run(tt.name, func(t *testing.T) {
 			db, mock, err := sqlmock.New()
@@ -478,44 +880,827 @@ func TestLogin(t *testing.T) {
 
 			repo := mocks.NewRepository(t)
 			tt.setupMock(mock, repo)
-			svc := NewServiceImpl(repo, db)
+			svc := NewServiceImpl(repo, db, nil)
 
 			result, err := svc.Login(context.Background(), loginRequest)
-			if err != nil {
-				assert.Error(t, err)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, result)
 				return
 			}
 
-			if tt.expectedErr {
-				assert.Error(t, err)
-				return
-			}
+			assert.NoError(t, err)
+			assert.Equal(t, username, result.Username)
+			assert.NotEmpty(t, result.RefreshToken)
 
-			assert.Equal(t, result, tt.expectedResult)
+			token, err := jwt.Parse(result.AccessToken, func(token *jwt.Token) (interface{}, error) {
+				return []byte("test-secret"), nil
+			}, jwt.WithValidMethods([]string{"HS256"}))
+			assert.NoError(t, err)
+			claims := token.Claims.(jwt.MapClaims)
+			assert.Equal(t, username, claims["username"])
+			assert.NotNil(t, claims["exp"])
+			assert.NotEmpty(t, claims["jti"])
 
-			mock.ExpectationsWereMet()
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
 
-func TestDeleteOrder(t *testing.T) {
-	id := "1"
+func TestLoginTwoFactor(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	id := uuid.New()
+	hash, err := helper.HashPassword("admin123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := helper.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := &domain.Admin{Id: id, Username: "admin", Password: hash, Role: domain.RoleOwner, TwoFactorEnabled: true, TOTPSecret: secret}
+
+	db, dbmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	repo := mocks.NewRepository(t)
+	repo.On("Login", mock.Anything, mock.Anything, mock.Anything).Return(admin, nil)
+	svc := NewServiceImpl(repo, db, nil)
+
+	challenge, err := svc.Login(context.Background(), &domain.Admin{Username: "admin", Password: "admin123"})
+	assert.NoError(t, err)
+	assert.True(t, challenge.TwoFactorRequired)
+	assert.Empty(t, challenge.AccessToken)
+	assert.NotEmpty(t, challenge.ChallengeToken)
+
+	code, err := helper.GenerateTOTPCode(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []struct {
 		name        string
-		setupMock   func(mock sqlmock.Sqlmock, repo *mocks.Repository)
-		expectedErr bool
+		request     *web.TwoFactorLoginRequest
+		setupMock   func()
+		expectedErr error
 	}{
 		{
-			name: "Success",
-			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
-				sqlmock.ExpectBegin()
-				repo.On("DeleteOrder", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-				sqlmock.ExpectCommit()
+			name:        "Invalid challenge token",
+			request:     &web.TwoFactorLoginRequest{ChallengeToken: "not-a-challenge", Code: code},
+			setupMock:   func() {},
+			expectedErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "Replayed code",
+			request: &web.TwoFactorLoginRequest{ChallengeToken: challenge.ChallengeToken, Code: code},
+			setupMock: func() {
+				repo.On("GetAdminById", mock.Anything, mock.Anything, id).Return(admin, nil).Once()
+				dbmock.ExpectBegin()
+				repo.On("UpdateAdminTOTPStep", mock.Anything, mock.Anything, id, mock.AnythingOfType("int64")).Return(sql.ErrNoRows).Once()
+				dbmock.ExpectRollback()
 			},
-			expectedErr: false,
+			expectedErr: ErrInvalidTwoFactorCode,
 		},
-	}
-
+		{
+			name:    "Valid code issues tokens",
+			request: &web.TwoFactorLoginRequest{ChallengeToken: challenge.ChallengeToken, Code: code},
+			setupMock: func() {
+				repo.On("GetAdminById", mock.Anything, mock.Anything, id).Return(admin, nil).Once()
+				dbmock.ExpectBegin()
+				repo.On("UpdateAdminTOTPStep", mock.Anything, mock.Anything, id, mock.AnythingOfType("int64")).Return(nil).Once()
+				repo.On("AddRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				dbmock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "Recovery code",
+			request: &web.TwoFactorLoginRequest{ChallengeToken: challenge.ChallengeToken, RecoveryCode: "ABCDE-FGHIJ"},
+			setupMock: func() {
+				repo.On("GetAdminById", mock.Anything, mock.Anything, id).Return(admin, nil).Once()
+				dbmock.ExpectBegin()
+				repo.On("UseRecoveryCode", mock.Anything, mock.Anything, id, helper.HashToken("abcde-fghij")).Return(nil).Once()
+				repo.On("AddRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				dbmock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			result, err := svc.VerifyTwoFactor(context.Background(), tt.request)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, result.AccessToken)
+				assert.NotEmpty(t, result.RefreshToken)
+			}
+
+			assert.NoError(t, dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	adminId := uuid.New()
+	refreshToken := "refresh-token"
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		setupMock   func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
+	}{
+		{
+			name: "Success rotates token",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				stored := &domain.RefreshToken{Id: "token-1", FamilyId: "family-1", AdminId: adminId, ExpiresAt: time.Now().Add(time.Hour)}
+				repo.On("GetRefreshToken", mock.Anything, mock.Anything, helper.HashToken(refreshToken)).Return(stored, nil)
+				repo.On("GetAdminById", mock.Anything, mock.Anything, adminId).Return(&domain.Admin{Id: adminId, Username: "admin"}, nil)
+				sqlmock.ExpectBegin()
+				repo.On("RevokeRefreshToken", mock.Anything, mock.Anything, "token-1").Return(nil)
+				repo.On("AddRefreshToken", mock.Anything, mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+					return token.FamilyId == "family-1" && token.Id != "token-1"
+				})).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Unknown token",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				repo.On("GetRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Expired token",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				stored := &domain.RefreshToken{Id: "token-1", FamilyId: "family-1", AdminId: adminId, ExpiresAt: time.Now().Add(-time.Hour)}
+				repo.On("GetRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(stored, nil)
+			},
+			expectedErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Reused token revokes family",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				stored := &domain.RefreshToken{Id: "token-1", FamilyId: "family-1", AdminId: adminId, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+				repo.On("GetRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(stored, nil)
+				sqlmock.ExpectBegin()
+				repo.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, "family-1").Return(nil)
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "Concurrent rotation revokes family",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				stored := &domain.RefreshToken{Id: "token-1", FamilyId: "family-1", AdminId: adminId, ExpiresAt: time.Now().Add(time.Hour)}
+				repo.On("GetRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(stored, nil)
+				repo.On("GetAdminById", mock.Anything, mock.Anything, adminId).Return(&domain.Admin{Id: adminId, Username: "admin"}, nil)
+				sqlmock.ExpectBegin()
+				repo.On("RevokeRefreshToken", mock.Anything, mock.Anything, "token-1").Return(sql.ErrNoRows)
+				sqlmock.ExpectRollback()
+				sqlmock.ExpectBegin()
+				repo.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, "family-1").Return(nil)
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: ErrRefreshTokenReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(mock, repo)
+			svc := NewServiceImpl(repo, db, nil)
+
+			result, err := svc.RefreshToken(context.Background(), refreshToken)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, result.AccessToken)
+				assert.NotEmpty(t, result.RefreshToken)
+				assert.NotEqual(t, refreshToken, result.RefreshToken)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	jti := uuid.NewString()
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	ctx := helper.WithClaims(context.Background(), &helper.Claims{
+		Username: "admin",
+		Roles:    []string{domain.RoleOwner},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		setupMock    func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr  bool
+	}{
+		{
+			name:         "Revokes access token and refresh family",
+			refreshToken: "refresh-token",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				stored := &domain.RefreshToken{Id: "token-1", FamilyId: "family-1"}
+				repo.On("GetRefreshToken", mock.Anything, mock.Anything, helper.HashToken("refresh-token")).Return(stored, nil)
+				sqlmock.ExpectBegin()
+				repo.On("AddRevokedAccessToken", mock.Anything, mock.Anything, jti, expiresAt).Return(nil)
+				repo.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, "family-1").Return(nil)
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: false,
+		},
+		{
+			name: "Access token only",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("AddRevokedAccessToken", mock.Anything, mock.Anything, jti, expiresAt).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: false,
+		},
+		{
+			name: "Denylist insert failed",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("AddRevokedAccessToken", mock.Anything, mock.Anything, jti, expiresAt).Return(errors.New("insert failed"))
+				sqlmock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(mock, repo)
+			svc := NewServiceImpl(repo, db, nil)
+
+			err = svc.Logout(ctx, tt.refreshToken)
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAddAdmin(t *testing.T) {
+	request := &web.CreateAdminRequest{Username: "kitchen1", Password: "secret123", Role: domain.RoleKitchen}
+
+	tests := []struct {
+		name        string
+		setupMock   func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
+	}{
+		{
+			name: "Success stores hashed password",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				repo.On("Login", mock.Anything, mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+				sqlmock.ExpectBegin()
+				repo.On("AddAdmin", mock.Anything, mock.Anything, mock.MatchedBy(func(admin *domain.Admin) bool {
+					return admin.Username == "kitchen1" &&
+						admin.Role == domain.RoleKitchen &&
+						admin.Password != "secret123" &&
+						helper.ComparePassword(admin.Password, "secret123") == nil
+				})).Return(func(ctx context.Context, tx *sql.Tx, admin *domain.Admin) (*domain.Admin, error) {
+					return admin, nil
+				})
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Username taken",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				repo.On("Login", mock.Anything, mock.Anything, mock.Anything).Return(&domain.Admin{Username: "kitchen1"}, nil)
+			},
+			expectedErr: ErrUsernameTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(mock, repo)
+			svc := NewServiceImpl(repo, db, nil)
+
+			result, err := svc.AddAdmin(context.Background(), request)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "kitchen1", result.Username)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDisableAdmin(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name        string
+		actorId     uuid.UUID
+		setupMock   func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
+	}{
+		{
+			name:    "Success revokes sessions",
+			actorId: uuid.New(),
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("UpdateAdminStatus", mock.Anything, mock.Anything, id, mock.AnythingOfType("*time.Time")).Return(nil)
+				repo.On("RevokeAdminRefreshTokens", mock.Anything, mock.Anything, id).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "Cannot disable self",
+			actorId:     id,
+			setupMock:   func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {},
+			expectedErr: ErrCannotModifySelf,
+		},
+		{
+			name:    "Admin not found",
+			actorId: uuid.New(),
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("UpdateAdminStatus", mock.Anything, mock.Anything, id, mock.AnythingOfType("*time.Time")).Return(sql.ErrNoRows)
+				sqlmock.ExpectRollback()
+			},
+			expectedErr: ErrAdminNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(mock, repo)
+			svc := NewServiceImpl(repo, db, nil)
+
+			err = svc.DisableAdmin(adminContext(tt.actorId), id)
+			assert.Equal(t, tt.expectedErr, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	id := uuid.New()
+	hash, err := helper.HashPassword("old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		request     *web.ChangePasswordRequest
+		setupMock   func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
+	}{
+		{
+			name:    "Success",
+			request: &web.ChangePasswordRequest{CurrentPassword: "old-password", NewPassword: "new-password"},
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				repo.On("GetAdminById", mock.Anything, mock.Anything, id).Return(&domain.Admin{Id: id, Username: "admin", Password: hash}, nil)
+				sqlmock.ExpectBegin()
+				repo.On("UpdateAdminPassword", mock.Anything, mock.Anything, id, mock.MatchedBy(func(password string) bool {
+					return helper.ComparePassword(password, "new-password") == nil
+				})).Return(nil)
+				repo.On("RevokeAdminRefreshTokens", mock.Anything, mock.Anything, id).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "Wrong current password",
+			request: &web.ChangePasswordRequest{CurrentPassword: "wrong-password", NewPassword: "new-password"},
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				repo.On("GetAdminById", mock.Anything, mock.Anything, id).Return(&domain.Admin{Id: id, Username: "admin", Password: hash}, nil)
+			},
+			expectedErr: ErrIncorrectPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(mock, repo)
+			svc := NewServiceImpl(repo, db, nil)
+
+			err = svc.ChangePassword(adminContext(id), tt.request)
+			assert.Equal(t, tt.expectedErr, err)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateOrder(t *testing.T) {
+	reserved := func(want bool) interface{} {
+		return mock.MatchedBy(func(order *domain.Orders) bool { return order.StockReserved == want })
+	}
+	movement := func(kind string, productId string, delta int) interface{} {
+		return mock.MatchedBy(func(m *domain.StockMovement) bool {
+			return m.Kind == kind && m.ProductId == productId && m.Delta == delta && *m.OrderId == "order-1"
+		})
+	}
+	change := func(from string, to string) interface{} {
+		return mock.MatchedBy(func(c *domain.OrderStatusChange) bool {
+			return c.OrderId == "order-1" && c.FromStatus == from && c.ToStatus == to && c.Note == "Called the customer"
+		})
+	}
+
+	tests := []struct {
+		name            string
+		status          string
+		setupMock       func(dbmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr     error
+		expectedMessage string
+	}{
+		{
+			name:   "Confirming reserves stock",
+			status: "confirmed",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "pending", Version: 3}, nil)
+				repo.On("UpdateOrder", mock.Anything, mock.Anything, reserved(true), "order-1", 3).Return(nil)
+				repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, change("pending", "confirmed")).Return(nil)
+				repo.On("GetOrderStockLines", mock.Anything, mock.Anything, "order-1").Return([]*domain.StockLine{
+					{ProductId: "PRD001", ProductName: "Nasi Box", Quantity: 100},
+					{ProductId: "PRD002", ProductName: "Es Teh", Quantity: 100},
+				}, nil)
+				repo.On("MoveStock", mock.Anything, mock.Anything, movement(domain.StockReservation, "PRD001", -100)).Return(nil)
+				repo.On("MoveStock", mock.Anything, mock.Anything, movement(domain.StockReservation, "PRD002", -100)).Return(nil)
+				repo.On("ReserveOrderVariantStock", mock.Anything, mock.Anything, "order-1").Return("", nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Not enough stock",
+			status: "confirmed",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "pending", Version: 3}, nil)
+				repo.On("UpdateOrder", mock.Anything, mock.Anything, reserved(true), "order-1", 3).Return(nil)
+				repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, change("pending", "confirmed")).Return(nil)
+				repo.On("GetOrderStockLines", mock.Anything, mock.Anything, "order-1").Return([]*domain.StockLine{
+					{ProductId: "PRD001", ProductName: "Nasi Box", Quantity: 100},
+				}, nil)
+				repo.On("MoveStock", mock.Anything, mock.Anything, mock.Anything).Return(&domain.Error{Kind: domain.KindConflict, Entity: "stock"})
+				dbmock.ExpectRollback()
+			},
+			expectedErr:     ErrInsufficientStock,
+			expectedMessage: "Not enough stock of Nasi Box to confirm this order.",
+		},
+		{
+			name:   "Cancelling releases reserved stock",
+			status: "cancelled",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "confirmed", StockReserved: true, Version: 3}, nil)
+				repo.On("UpdateOrder", mock.Anything, mock.Anything, reserved(false), "order-1", 3).Return(nil)
+				repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, change("confirmed", "cancelled")).Return(nil)
+				repo.On("GetOrderReservedStock", mock.Anything, mock.Anything, "order-1").Return([]*domain.StockLine{
+					{ProductId: "PRD001", ProductName: "Nasi Box", Quantity: 2},
+				}, nil)
+				repo.On("MoveStock", mock.Anything, mock.Anything, movement(domain.StockRestock, "PRD001", 2)).Return(nil)
+				repo.On("ReleaseOrderVariantStock", mock.Anything, mock.Anything, "order-1").Return(nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Not enough stock of a variant",
+			status: "confirmed",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "pending", Version: 3}, nil)
+				repo.On("UpdateOrder", mock.Anything, mock.Anything, reserved(true), "order-1", 3).Return(nil)
+				repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, change("pending", "confirmed")).Return(nil)
+				repo.On("GetOrderStockLines", mock.Anything, mock.Anything, "order-1").Return([]*domain.StockLine{
+					{ProductId: "PRD001", ProductName: "Nasi Box", Quantity: 2},
+				}, nil)
+				repo.On("MoveStock", mock.Anything, mock.Anything, movement(domain.StockReservation, "PRD001", -2)).Return(nil)
+				repo.On("ReserveOrderVariantStock", mock.Anything, mock.Anything, "order-1").Return("Large", nil)
+				dbmock.ExpectRollback()
+			},
+			expectedErr:     ErrInsufficientStock,
+			expectedMessage: "Not enough stock of Large to confirm this order.",
+		},
+		{
+			name:   "Stock is reserved only once",
+			status: "preparing",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "confirmed", StockReserved: true, Version: 3}, nil)
+				repo.On("UpdateOrder", mock.Anything, mock.Anything, reserved(true), "order-1", 3).Return(nil)
+				repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, change("confirmed", "preparing")).Return(nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Completing uses the reserved stock up",
+			status: "completed",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "delivering", StockReserved: true, Version: 3}, nil)
+				repo.On("UpdateOrder", mock.Anything, mock.Anything, reserved(false), "order-1", 3).Return(nil)
+				repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, change("delivering", "completed")).Return(nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Refunding a completed order restocks nothing",
+			status: "refunded",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "completed", StockReserved: true, Version: 3}, nil)
+				repo.On("UpdateOrder", mock.Anything, mock.Anything, reserved(false), "order-1", 3).Return(nil)
+				repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, change("completed", "refunded")).Return(nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Cancelling an unconfirmed order",
+			status: "cancelled",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "pending", Version: 3}, nil)
+				repo.On("UpdateOrder", mock.Anything, mock.Anything, reserved(false), "order-1", 3).Return(nil)
+				repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, change("pending", "cancelled")).Return(nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Illegal transition",
+			status: "completed",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "pending", Version: 3}, nil)
+				dbmock.ExpectRollback()
+			},
+			expectedErr:     ErrInvalidOrderTransition,
+			expectedMessage: "An order cannot go from pending to completed.",
+		},
+		{
+			name:   "Unknown status",
+			status: "done",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "ready", Version: 3}, nil)
+				dbmock.ExpectRollback()
+			},
+			expectedErr: ErrUnknownOrderStatus,
+		},
+		{
+			name:   "Stale version",
+			status: "confirmed",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "confirmed", Version: 4}, nil)
+				dbmock.ExpectRollback()
+			},
+			expectedErr: domain.NewError(domain.KindStale, "order", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbmock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(dbmock, repo)
+
+			// A client cannot claim stock was reserved.
+			entity := &domain.Orders{Status: tt.status, StockReserved: true, Note: "Called the customer"}
+			err = NewServiceImpl(repo, db, nil).UpdateOrder(context.Background(), entity, "order-1", 3)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				if tt.expectedMessage != "" {
+					assert.Equal(t, tt.expectedMessage, err.(*domain.Error).Message)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateOrderReleasesWhatWasReserved(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// The ledger the repository would keep for order-1.
+	var reserved []*domain.StockLine
+	repo := mocks.NewRepository(t)
+	repo.On("UpdateOrder", mock.Anything, mock.Anything, mock.Anything, "order-1", mock.Anything).Return(nil)
+	repo.On("AddOrderStatusChange", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	repo.On("ReserveOrderVariantStock", mock.Anything, mock.Anything, "order-1").Return("", nil)
+	repo.On("ReleaseOrderVariantStock", mock.Anything, mock.Anything, "order-1").Return(nil)
+	repo.On("MoveStock", mock.Anything, mock.Anything, mock.MatchedBy(func(m *domain.StockMovement) bool {
+		return m.Kind == domain.StockReservation
+	})).Run(func(args mock.Arguments) {
+		m := args.Get(2).(*domain.StockMovement)
+		reserved = append(reserved, &domain.StockLine{ProductId: m.ProductId, Quantity: -m.Delta})
+	}).Return(nil)
+
+	// Confirm while the package holds two boxes and one tea per order.
+	dbmock.ExpectBegin()
+	repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "pending", Version: 3}, nil).Once()
+	repo.On("GetOrderStockLines", mock.Anything, mock.Anything, "order-1").Return([]*domain.StockLine{
+		{ProductId: "PRD001", ProductName: "Nasi Box", Quantity: 20},
+		{ProductId: "PRD002", ProductName: "Es Teh", Quantity: 10},
+	}, nil).Once()
+	dbmock.ExpectCommit()
+
+	svc := NewServiceImpl(repo, db, nil)
+	err = svc.UpdateOrder(context.Background(), &domain.Orders{Status: "confirmed"}, "order-1", 3)
+	assert.NoError(t, err)
+
+	// The package now holds something else; cancelling must not look at it.
+	dbmock.ExpectBegin()
+	repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, "order-1").Return(&domain.Orders{Id: "order-1", Status: "confirmed", StockReserved: true, Version: 4}, nil).Once()
+	repo.On("GetOrderReservedStock", mock.Anything, mock.Anything, "order-1").Return(func(context.Context, *sql.Tx, string) []*domain.StockLine { return reserved }, nil).Once()
+	repo.On("MoveStock", mock.Anything, mock.Anything, mock.MatchedBy(func(m *domain.StockMovement) bool {
+		return m.Kind == domain.StockRestock && m.ProductId == "PRD001" && m.Delta == 20
+	})).Return(nil).Once()
+	repo.On("MoveStock", mock.Anything, mock.Anything, mock.MatchedBy(func(m *domain.StockMovement) bool {
+		return m.Kind == domain.StockRestock && m.ProductId == "PRD002" && m.Delta == 10
+	})).Return(nil).Once()
+	dbmock.ExpectCommit()
+
+	err = svc.UpdateOrder(context.Background(), &domain.Orders{Status: "cancelled"}, "order-1", 4)
+	assert.NoError(t, err)
+
+	repo.AssertNumberOfCalls(t, "GetOrderStockLines", 1)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestGetPackages(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := mocks.NewRepository(t)
+	repo.On("GetPackages", mock.Anything, mock.Anything).Return([]*domain.Package{
+		{
+			Id:       "PKG001",
+			Pricing:  domain.PackagePricingDiscount,
+			Discount: 150000,
+			Items: []*domain.PackageItem{
+				{ProductId: "PRD001", Quantity: 100, UnitPrice: 25000, Stock: 250},
+				{ProductId: "PRD002", Quantity: 100, UnitPrice: 5000, Stock: 800},
+			},
+		},
+		{
+			Id:      "PKG002",
+			Pricing: domain.PackagePricingFixed,
+			Price:   900000,
+			Items: []*domain.PackageItem{
+				{ProductId: "PRD001", Quantity: 50, UnitPrice: 25000, Stock: 250},
+				{ProductId: "PRD003", Quantity: 50, UnitPrice: 4000, Stock: 500, Deleted: true},
+			},
+		},
+	}, nil)
+
+	packages, err := NewServiceImpl(repo, db, nil).GetPackages(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, packages, 2) {
+		assert.Equal(t, 2850000, packages[0].Price)
+		assert.Equal(t, 2, packages[0].Available)
+		assert.Equal(t, 900000, packages[1].Price)
+		assert.Equal(t, 0, packages[1].Available)
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	id := "1"
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, id).Return(&domain.Orders{Id: id, Status: "pending", Version: 1}, nil)
+				repo.On("DeleteOrder", mock.Anything, mock.Anything, mock.Anything, 1).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+		},
+		{
+			name: "Reserved stock is given back",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, id).Return(&domain.Orders{Id: id, Status: "preparing", StockReserved: true, Version: 1}, nil)
+				repo.On("GetOrderReservedStock", mock.Anything, mock.Anything, id).Return([]*domain.StockLine{
+					{ProductId: "PRD001", ProductName: "Nasi Box", Quantity: 5},
+				}, nil)
+				repo.On("MoveStock", mock.Anything, mock.Anything, mock.MatchedBy(func(m *domain.StockMovement) bool {
+					return m.Kind == domain.StockRestock && m.ProductId == "PRD001" && m.Delta == 5 && m.Reason == "Order deleted" && *m.OrderId == id
+				})).Return(nil)
+				repo.On("ReleaseOrderVariantStock", mock.Anything, mock.Anything, id).Return(nil)
+				repo.On("DeleteOrder", mock.Anything, mock.Anything, mock.Anything, 1).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+		},
+		{
+			name: "Completed order keeps its stock used",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, id).Return(&domain.Orders{Id: id, Status: "completed", StockReserved: true, Version: 1}, nil)
+				repo.On("DeleteOrder", mock.Anything, mock.Anything, mock.Anything, 1).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+		},
+		{
+			name: "Refunded order keeps its stock used",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, id).Return(&domain.Orders{Id: id, Status: "refunded", StockReserved: true, Version: 1}, nil)
+				repo.On("DeleteOrder", mock.Anything, mock.Anything, mock.Anything, 1).Return(nil)
+				sqlmock.ExpectCommit()
+			},
+		},
+		{
+			name: "Stale version",
+			setupMock: func(sqlmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				sqlmock.ExpectBegin()
+				repo.On("GetOrderForUpdate", mock.Anything, mock.Anything, id).Return(&domain.Orders{Id: id, StockReserved: true, Version: 2}, nil)
+				sqlmock.ExpectRollback()
+			},
+			expectedErr: domain.NewError(domain.KindStale, "order", nil),
+		},
+	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			db, mock, err := sqlmock.New()
@@ -525,12 +1710,128 @@ func TestDeleteOrder(t *testing.T) {
 			repo := mocks.NewRepository(t)
 			tt.setupMock(mock, repo)
 
-			svc := NewServiceImpl(repo, db)
+			svc := NewServiceImpl(repo, db, nil)
+
+			err = svc.DeleteOrder(context.Background(), id, 1)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAddOptionGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *web.OptionGroupRequest
+		setupMock   func(dbmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
+	}{
+		{
+			name:    "Success",
+			request: &web.OptionGroupRequest{Name: "Spice level", MinSelect: 1, MaxSelect: 1},
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("AddOptionGroup", mock.Anything, mock.Anything, mock.MatchedBy(func(group *domain.OptionGroup) bool {
+					return group.ProductId == "PRD001" && group.Id != "" && group.MinSelect == 1
+				})).Return(nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name:        "Max below min",
+			request:     &web.OptionGroupRequest{Name: "Extras", MinSelect: 3, MaxSelect: 2},
+			setupMock:   func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {},
+			expectedErr: ErrInvalidOptionGroup,
+		},
+		{
+			name:    "Product not found",
+			request: &web.OptionGroupRequest{Name: "Extras"},
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("AddOptionGroup", mock.Anything, mock.Anything, mock.Anything).Return(domain.NewError(domain.KindNotFound, "product", sql.ErrNoRows))
+				dbmock.ExpectRollback()
+			},
+			expectedErr: ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbmock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			repo := mocks.NewRepository(t)
+			tt.setupMock(dbmock, repo)
+
+			group, err := NewServiceImpl(repo, db, nil).AddOptionGroup(context.Background(), "PRD001", tt.request)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []*domain.ProductOption{}, group.Options)
+			}
+			assert.NoError(t, dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(dbmock sqlmock.Sqlmock, repo *mocks.Repository)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("DeleteCategory", mock.Anything, mock.Anything, 2).Return(nil)
+				dbmock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not found",
+			setupMock: func(dbmock sqlmock.Sqlmock, repo *mocks.Repository) {
+				dbmock.ExpectBegin()
+				repo.On("DeleteCategory", mock.Anything, mock.Anything, 2).Return(domain.NewError(domain.KindNotFound, "category", sql.ErrNoRows))
+				dbmock.ExpectRollback()
+			},
+			expectedErr: ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbmock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
 
-			err = svc.DeleteOrder(context.Background(), id)
+			repo := mocks.NewRepository(t)
+			tt.setupMock(dbmock, repo)
 
-			assert.Nil(t, err)
-			mock.ExpectationsWereMet()
+			err = NewServiceImpl(repo, db, nil).DeleteCategory(context.Background(), 2)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, dbmock.ExpectationsWereMet())
 		})
 	}
 }
+
+func adminContext(id uuid.UUID) context.Context {
+	return helper.WithClaims(context.Background(), &helper.Claims{
+		Username: "admin",
+		Roles:    []string{domain.RoleOwner},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:      uuid.NewString(),
+			Subject: id.String(),
+		},
+	})
+}
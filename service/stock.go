@@ -0,0 +1,170 @@
+package service
+
+import (
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"catering-admin-go/logger"
+	"catering-admin-go/web"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func (svc *ServiceImpl) AddStockMovement(ctx context.Context, productId string, request *web.StockMovementRequest) (data *domain.StockMovement, err error) {
+	if request.Kind == domain.StockWaste && request.Delta > 0 {
+		return nil, ErrInvalidStockMovement
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add stock movement", "error", err.Error())
+		return nil, err
+	}
+
+	date := time.Now()
+	defer helper.WithTransaction(tx, &err)
+	data = &domain.StockMovement{
+		ProductId: productId,
+		Kind:      request.Kind,
+		Delta:     request.Delta,
+		Reason:    request.Reason,
+		CreatedAt: &date,
+	}
+	if err = svc.repo.MoveStock(ctx, tx, data); err != nil {
+		logger.GetLogger("service-log").Log("add stock movement", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) GetStockMovements(ctx context.Context, filter *domain.StockMovementFilter) ([]*domain.StockMovement, *web.Meta, error) {
+	if _, err := svc.repo.GetProduct(ctx, svc.db, filter.ProductId); err != nil {
+		logger.GetLogger("service-log").Log("get stock movements", "error", err.Error())
+		return nil, nil, err
+	}
+	total, err := svc.repo.CountStockMovements(ctx, svc.db, filter.ProductId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get stock movements", "error", err.Error())
+		return nil, nil, err
+	}
+
+	query := *filter
+	query.Limit = filter.Limit + 1
+	movements, err := svc.repo.GetStockMovements(ctx, svc.db, &query)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get stock movements", "error", err.Error())
+		return nil, nil, err
+	}
+	if movements == nil {
+		movements = []*domain.StockMovement{}
+	}
+
+	meta := &web.Meta{Total: total, PageSize: filter.Limit}
+	if len(movements) > filter.Limit {
+		movements = movements[:filter.Limit]
+		last := movements[len(movements)-1]
+		meta.NextCursor = helper.EncodeCursor(domain.Cursor{Id: strconv.FormatInt(last.Id, 10)})
+	}
+
+	return movements, meta, nil
+}
+
+// setStock records the adjustment that brings product to stock, if any.
+func (svc *ServiceImpl) setStock(ctx context.Context, tx *sql.Tx, product *domain.Domain, stock int, reason string) error {
+	if stock == product.Stock {
+		return nil
+	}
+
+	date := time.Now()
+	movement := &domain.StockMovement{
+		ProductId: product.Id,
+		Kind:      domain.StockAdjustment,
+		Delta:     stock - product.Stock,
+		Reason:    reason,
+		CreatedAt: &date,
+	}
+	if err := svc.repo.MoveStock(ctx, tx, movement); err != nil {
+		return err
+	}
+	product.Stock = movement.StockAfter
+	return nil
+}
+
+// notEnoughStock reports that name cannot cover the order being confirmed.
+func notEnoughStock(name string, err error) error {
+	return &domain.Error{
+		Kind:    ErrInsufficientStock.Kind,
+		Entity:  ErrInsufficientStock.Entity,
+		Message: fmt.Sprintf("Not enough stock of %s to confirm this order.", name),
+		Err:     err,
+	}
+}
+
+// reserveOrderStock takes the stock an order needs from each of its products,
+// reading the package's items as they are now, and from its variant and
+// options where they keep stock of their own.
+func (svc *ServiceImpl) reserveOrderStock(ctx context.Context, tx *sql.Tx, orderId string) error {
+	lines, err := svc.repo.GetOrderStockLines(ctx, tx, orderId)
+	if err != nil {
+		return err
+	}
+
+	date := time.Now()
+	for _, line := range lines {
+		err := svc.repo.MoveStock(ctx, tx, &domain.StockMovement{
+			ProductId: line.ProductId,
+			Kind:      domain.StockReservation,
+			Delta:     -line.Quantity,
+			Reason:    "Order confirmed",
+			OrderId:   &orderId,
+			CreatedAt: &date,
+		})
+		if errors.Is(err, ErrInsufficientStock) {
+			return notEnoughStock(line.ProductName, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	short, err := svc.repo.ReserveOrderVariantStock(ctx, tx, orderId)
+	if err != nil {
+		return err
+	}
+	if short != "" {
+		return notEnoughStock(short, nil)
+	}
+
+	return nil
+}
+
+// releaseOrderStock gives back what the order's reservations took, so an
+// edit to its package since then does not change what is restocked, along
+// with what its variant and options hold.
+func (svc *ServiceImpl) releaseOrderStock(ctx context.Context, tx *sql.Tx, orderId string, reason string) error {
+	lines, err := svc.repo.GetOrderReservedStock(ctx, tx, orderId)
+	if err != nil {
+		return err
+	}
+
+	date := time.Now()
+	for _, line := range lines {
+		err := svc.repo.MoveStock(ctx, tx, &domain.StockMovement{
+			ProductId: line.ProductId,
+			Kind:      domain.StockRestock,
+			Delta:     line.Quantity,
+			Reason:    reason,
+			OrderId:   &orderId,
+			CreatedAt: &date,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return svc.repo.ReleaseOrderVariantStock(ctx, tx, orderId)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"catering-admin-go/logger"
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	trashPurgeInterval    = time.Hour
+	trashPurgeTimeout     = time.Minute
+)
+
+// TrashPurger periodically removes products that have been in the trash for
+// longer than PRODUCT_TRASH_RETENTION (a Go duration, default 720h).
+type TrashPurger struct {
+	svc       Service
+	retention time.Duration
+}
+
+// NewTrashPurger reads the retention from the environment; call Start to
+// begin purging.
+func NewTrashPurger(svc Service) (*TrashPurger, error) {
+	retention := defaultTrashRetention
+	if raw := os.Getenv("PRODUCT_TRASH_RETENTION"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid PRODUCT_TRASH_RETENTION %q", raw)
+		}
+		retention = parsed
+	}
+
+	return &TrashPurger{svc: svc, retention: retention}, nil
+}
+
+// Start purges in the background until the returned function is called.
+func (p *TrashPurger) Start() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go p.run(ctx)
+	return cancel
+}
+
+func (p *TrashPurger) run(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *TrashPurger) purge(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, trashPurgeTimeout)
+	defer cancel()
+
+	purged, err := p.svc.PurgeDeletedProducts(ctx, p.retention)
+	if err != nil {
+		return
+	}
+	if purged > 0 {
+		logger.GetLogger("service-log").Log("purge products", "info", fmt.Sprintf("purged %d products", purged))
+	}
+}
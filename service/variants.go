@@ -0,0 +1,240 @@
+package service
+
+import (
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"catering-admin-go/logger"
+	"catering-admin-go/web"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+func (svc *ServiceImpl) AddProductVariant(ctx context.Context, productId string, request *web.VariantRequest) (data *domain.ProductVariant, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add product variant", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	data = &domain.ProductVariant{
+		Id:         uuid.NewString(),
+		ProductId:  productId,
+		Name:       request.Name,
+		PriceDelta: request.PriceDelta,
+		Stock:      request.Stock,
+		Position:   request.Position,
+	}
+	if err = svc.repo.AddProductVariant(ctx, tx, data); err != nil {
+		logger.GetLogger("service-log").Log("add product variant", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) UpdateProductVariant(ctx context.Context, productId string, variantId string, request *web.VariantRequest) (data *domain.ProductVariant, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update product variant", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	data = &domain.ProductVariant{
+		Id:         variantId,
+		ProductId:  productId,
+		Name:       request.Name,
+		PriceDelta: request.PriceDelta,
+		Stock:      request.Stock,
+		Position:   request.Position,
+	}
+	if err = svc.repo.UpdateProductVariant(ctx, tx, data); err != nil {
+		logger.GetLogger("service-log").Log("update product variant", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) DeleteProductVariant(ctx context.Context, productId string, variantId string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete product variant", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	if err = svc.repo.DeleteProductVariant(ctx, tx, productId, variantId); err != nil {
+		logger.GetLogger("service-log").Log("delete product variant", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func optionGroup(productId string, id string, request *web.OptionGroupRequest) (*domain.OptionGroup, error) {
+	if request.MaxSelect != 0 && request.MaxSelect < request.MinSelect {
+		return nil, ErrInvalidOptionGroup
+	}
+	return &domain.OptionGroup{
+		Id:        id,
+		ProductId: productId,
+		Name:      request.Name,
+		MinSelect: request.MinSelect,
+		MaxSelect: request.MaxSelect,
+		Position:  request.Position,
+	}, nil
+}
+
+func (svc *ServiceImpl) AddOptionGroup(ctx context.Context, productId string, request *web.OptionGroupRequest) (data *domain.OptionGroup, err error) {
+	data, err = optionGroup(productId, uuid.NewString(), request)
+	if err != nil {
+		return nil, err
+	}
+	data.Options = []*domain.ProductOption{}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add option group", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	if err = svc.repo.AddOptionGroup(ctx, tx, data); err != nil {
+		logger.GetLogger("service-log").Log("add option group", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpdateOptionGroup changes the group itself; the response leaves Options
+// out since they are not touched.
+func (svc *ServiceImpl) UpdateOptionGroup(ctx context.Context, productId string, groupId string, request *web.OptionGroupRequest) (data *domain.OptionGroup, err error) {
+	data, err = optionGroup(productId, groupId, request)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update option group", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	if err = svc.repo.UpdateOptionGroup(ctx, tx, data); err != nil {
+		logger.GetLogger("service-log").Log("update option group", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) DeleteOptionGroup(ctx context.Context, productId string, groupId string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete option group", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	if err = svc.repo.DeleteOptionGroup(ctx, tx, productId, groupId); err != nil {
+		logger.GetLogger("service-log").Log("delete option group", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (svc *ServiceImpl) AddProductOption(ctx context.Context, productId string, groupId string, request *web.OptionRequest) (data *domain.ProductOption, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add product option", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	data = &domain.ProductOption{
+		Id:         uuid.NewString(),
+		GroupId:    groupId,
+		Name:       request.Name,
+		PriceDelta: request.PriceDelta,
+		Stock:      request.Stock,
+		Position:   request.Position,
+	}
+	if err = svc.repo.AddProductOption(ctx, tx, productId, data); err != nil {
+		logger.GetLogger("service-log").Log("add product option", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) UpdateProductOption(ctx context.Context, productId string, groupId string, optionId string, request *web.OptionRequest) (data *domain.ProductOption, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update product option", "error", err.Error())
+		return nil, err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	data = &domain.ProductOption{
+		Id:         optionId,
+		GroupId:    groupId,
+		Name:       request.Name,
+		PriceDelta: request.PriceDelta,
+		Stock:      request.Stock,
+		Position:   request.Position,
+	}
+	if err = svc.repo.UpdateProductOption(ctx, tx, productId, data); err != nil {
+		logger.GetLogger("service-log").Log("update product option", "error", err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (svc *ServiceImpl) DeleteProductOption(ctx context.Context, productId string, groupId string, optionId string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete product option", "error", err.Error())
+		return err
+	}
+
+	defer helper.WithTransaction(tx, &err)
+	if err = svc.repo.DeleteProductOption(ctx, tx, productId, groupId, optionId); err != nil {
+		logger.GetLogger("service-log").Log("delete product option", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// attachOrderOptions loads the options chosen on orders.
+func (svc *ServiceImpl) attachOrderOptions(ctx context.Context, orders ...*domain.Orders) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(orders))
+	byId := make(map[string]*domain.Orders, len(orders))
+	for i, order := range orders {
+		ids[i] = order.Id
+		byId[order.Id] = order
+		order.Options = []*domain.OrderOption{}
+	}
+
+	options, err := svc.repo.GetOrderOptions(ctx, svc.db, ids)
+	if err != nil {
+		return err
+	}
+	for _, option := range options {
+		if order, ok := byId[option.OrderId]; ok {
+			order.Options = append(order.Options, option)
+		}
+	}
+	return nil
+}
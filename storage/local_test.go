@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	store := &LocalStorage{Root: root, URLPrefix: "/uploads"}
+	ctx := context.Background()
+
+	err := store.Put(ctx, "products/PRD001/a.jpg", strings.NewReader("image"), "image/jpeg")
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(root, "products", "PRD001", "a.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "image", string(data))
+	assert.Equal(t, "/uploads/products/PRD001/a.jpg", store.URL("products/PRD001/a.jpg"))
+
+	assert.NoError(t, store.Delete(ctx, "products/PRD001/a.jpg"))
+	assert.NoError(t, store.Delete(ctx, "products/PRD001/a.jpg"), "deleting a missing file is not an error")
+	_, err = os.Stat(filepath.Join(root, "products", "PRD001", "a.jpg"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLocalStorageRejectsEscapingKeys(t *testing.T) {
+	store := &LocalStorage{Root: t.TempDir(), URLPrefix: "/uploads"}
+
+	for _, key := range []string{"", "../secret", "products/../../secret", "/etc/passwd", "products//a.jpg"} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"), "text/plain")
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
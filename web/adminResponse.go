@@ -1,6 +1,51 @@
 package web
 
+import (
+	"catering-admin-go/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type AdminResponse struct {
-	Username    string `json:"username"`
-	AccessToken string `json:"access_token"`
+	Username          string `json:"username"`
+	Role              string `json:"role,omitempty"`
+	AccessToken       string `json:"access_token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+// AdminAccountResponse is the public view of an admin account; it never
+// carries the password hash.
+type AdminAccountResponse struct {
+	Id               uuid.UUID  `json:"id"`
+	Username         string     `json:"username"`
+	Role             string     `json:"role"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	Disabled         bool       `json:"disabled"`
+	DisabledAt       *time.Time `json:"disabled_at"`
+	CreatedAt        *time.Time `json:"created_at"`
+	ModifiedAt       *time.Time `json:"modified_at"`
+}
+
+func ToAdminAccountResponse(admin *domain.Admin) *AdminAccountResponse {
+	return &AdminAccountResponse{
+		Id:               admin.Id,
+		Username:         admin.Username,
+		Role:             admin.Role,
+		TwoFactorEnabled: admin.TwoFactorEnabled,
+		Disabled:         admin.DisabledAt != nil,
+		DisabledAt:       admin.DisabledAt,
+		CreatedAt:        admin.CreatedAt,
+		ModifiedAt:       admin.ModifiedAt,
+	}
+}
+
+func ToAdminAccountResponses(admins []*domain.Admin) []*AdminAccountResponse {
+	responses := make([]*AdminAccountResponse, 0, len(admins))
+	for _, admin := range admins {
+		responses = append(responses, ToAdminAccountResponse(admin))
+	}
+	return responses
 }
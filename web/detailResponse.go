@@ -0,0 +1,20 @@
+package web
+
+import "catering-admin-go/domain"
+
+type ProductDetailResponse struct {
+	*domain.Domain
+	Variants     []*domain.ProductVariant `json:"variants"`
+	OptionGroups []*domain.OptionGroup    `json:"option_groups"`
+	RecentOrders []*domain.Orders         `json:"recent_orders"`
+}
+
+// OrderDetailResponse carries the ordered product or package; Product is
+// null when the product no longer exists or the order is for a package.
+// StatusHistory is the order's status changes, oldest first.
+type OrderDetailResponse struct {
+	*domain.Orders
+	Product       *domain.Domain              `json:"product"`
+	Package       *domain.Package             `json:"package"`
+	StatusHistory []*domain.OrderStatusChange `json:"status_history"`
+}
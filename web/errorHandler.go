@@ -0,0 +1,56 @@
+package web
+
+import (
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"catering-admin-go/logger"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+var domainErrorStatus = map[domain.ErrorKind]int{
+	domain.KindNotFound:        fiber.StatusNotFound,
+	domain.KindConflict:        fiber.StatusConflict,
+	domain.KindValidation:      fiber.StatusUnprocessableEntity,
+	domain.KindForeignKeyInUse: fiber.StatusConflict,
+	domain.KindStale:           fiber.StatusPreconditionFailed,
+}
+
+// ErrorHandler is the app-wide Fiber error handler. Handlers return
+// validation and domain errors as they are and wrap anything else in a
+// *fiber.Error whose message is safe to show; any other error becomes a
+// generic 500.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(&Response[[]domain.FieldError]{
+			Code:   fiber.StatusUnprocessableEntity,
+			Status: "Please correct the highlighted fields.",
+			Error:  string(domain.KindValidation),
+			Data:   helper.TranslateValidationErrors(validationErrs, c.Get(fiber.HeaderAcceptLanguage)),
+		})
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		code, ok := domainErrorStatus[domainErr.Kind]
+		if !ok {
+			code = fiber.StatusInternalServerError
+		}
+		return c.Status(code).JSON(&Response[any]{
+			Code:   code,
+			Status: domainErr.Message,
+			Error:  domainErr.Code(),
+		})
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return ErrorResponse(c, fiberErr.Code, fiberErr.Message, "")
+	}
+
+	logger.GetLogger("http-log").Log("unhandled error", "error", err.Error())
+	return ErrorResponse(c, fiber.StatusInternalServerError, "Something went wrong. Please try again later.", "")
+}
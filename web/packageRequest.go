@@ -0,0 +1,18 @@
+package web
+
+// PackageRequest creates a package or replaces all of its fields, items
+// included. Price is only used with fixed pricing and Discount only with
+// discount pricing.
+type PackageRequest struct {
+	Name        string               `json:"name" validate:"required,min=5,max=100"`
+	Description string               `json:"description" validate:"omitempty,max=1000"`
+	Pricing     string               `json:"pricing" validate:"required,oneof=fixed discount"`
+	Price       int                  `json:"price" validate:"required_if=Pricing fixed,min=0"`
+	Discount    int                  `json:"discount" validate:"min=0"`
+	Items       []PackageItemRequest `json:"items" validate:"required,min=1,max=50,unique=ProductId,dive"`
+}
+
+type PackageItemRequest struct {
+	ProductId string `json:"product_id" validate:"required,max=12"`
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
+}
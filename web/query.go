@@ -0,0 +1,134 @@
+package web
+
+import (
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"errors"
+	"strings"
+	"time"
+)
+
+const DefaultPageSize = 20
+
+var (
+	ErrInvalidPriceRange = errors.New("min_price must not be greater than max_price")
+	ErrInvalidTotalRange = errors.New("min_total must not be greater than max_total")
+	ErrInvalidDateRange  = errors.New("created_from must not be after created_to")
+)
+
+type ProductQuery struct {
+	Page       int    `query:"page" validate:"omitempty,min=1"`
+	PageSize   int    `query:"page_size" validate:"omitempty,min=1,max=100"`
+	Cursor     string `query:"cursor"`
+	Name       string `query:"name" validate:"omitempty,max=100"`
+	MinPrice   *int   `query:"min_price" validate:"omitempty,min=0"`
+	MaxPrice   *int   `query:"max_price" validate:"omitempty,min=0"`
+	InStock    bool   `query:"in_stock"`
+	CategoryId *int   `query:"category_id" validate:"omitempty,min=0"`
+	Sort       string `query:"sort" validate:"omitempty,oneof=name -name price -price stock -stock created_at -created_at"`
+}
+
+// ToFilter applies the defaults for missing parameters. A leading "-" on sort
+// means descending; a cursor takes precedence over page. category_id=0 picks
+// the products that are in no category.
+func (q *ProductQuery) ToFilter() (*domain.ProductFilter, error) {
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		return nil, ErrInvalidPriceRange
+	}
+
+	filter := &domain.ProductFilter{
+		Name:       strings.TrimSpace(q.Name),
+		MinPrice:   q.MinPrice,
+		MaxPrice:   q.MaxPrice,
+		InStock:    q.InStock,
+		CategoryId: q.CategoryId,
+		SortBy:     strings.TrimPrefix(q.Sort, "-"),
+		Desc:       strings.HasPrefix(q.Sort, "-"),
+		Limit:      q.PageSize,
+	}
+	if filter.SortBy == "" {
+		filter.SortBy = "name"
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPageSize
+	}
+
+	if q.Cursor != "" {
+		cursor, err := helper.DecodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := helper.ProductCursorValue(filter.SortBy, cursor.Value); err != nil {
+			return nil, err
+		}
+		filter.After = cursor
+	} else if q.Page > 1 {
+		filter.Offset = (q.Page - 1) * filter.Limit
+	}
+
+	return filter, nil
+}
+
+type OrderQuery struct {
+	PageSize    int      `query:"page_size" validate:"omitempty,min=1,max=100"`
+	Cursor      string   `query:"cursor"`
+	Status      string   `query:"status" validate:"omitempty,max=20"`
+	Username    string   `query:"username" validate:"omitempty,max=100"`
+	ProductId   string   `query:"product_id" validate:"omitempty,max=12"`
+	CreatedFrom string   `query:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo   string   `query:"created_to" validate:"omitempty,datetime=2006-01-02"`
+	MinTotal    *float64 `query:"min_total" validate:"omitempty,min=0"`
+	MaxTotal    *float64 `query:"max_total" validate:"omitempty,min=0"`
+}
+
+// ToFilter treats created_from and created_to as whole days, both inclusive.
+func (q *OrderQuery) ToFilter() (*domain.OrderFilter, error) {
+	if q.MinTotal != nil && q.MaxTotal != nil && *q.MinTotal > *q.MaxTotal {
+		return nil, ErrInvalidTotalRange
+	}
+
+	filter := &domain.OrderFilter{
+		Status:    q.Status,
+		Username:  q.Username,
+		ProductId: q.ProductId,
+		MinTotal:  q.MinTotal,
+		MaxTotal:  q.MaxTotal,
+		Limit:     q.PageSize,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPageSize
+	}
+
+	if q.CreatedFrom != "" {
+		from, err := time.ParseInLocation(time.DateOnly, q.CreatedFrom, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		filter.CreatedFrom = &from
+	}
+	if q.CreatedTo != "" {
+		to, err := time.ParseInLocation(time.DateOnly, q.CreatedTo, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		before := to.AddDate(0, 0, 1)
+		filter.CreatedBefore = &before
+	}
+	if filter.CreatedFrom != nil && filter.CreatedBefore != nil && !filter.CreatedFrom.Before(*filter.CreatedBefore) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if q.Cursor != "" {
+		cursor, err := helper.DecodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// Orders are paged by created_at, which the cursor carries as text.
+		if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+			return nil, helper.ErrInvalidCursor
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
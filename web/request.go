@@ -10,6 +10,29 @@ type Request struct {
 	Description string     `json:"description" validate:"omitempty,alphanum"`
 	Stock       int        `json:"stock" validate:"required,number"`
 	Price       int        `json:"price" validate:"required,number"`
+	CategoryId  *int       `json:"category_id" validate:"omitempty,min=1"`
 	CreatedAt   *time.Time `json:"created_at"`
 	ModifiedAt  *time.Time `json:"modified_at"`
 }
+
+type CreateAdminRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=100"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Role     string `json:"role" validate:"required,oneof=owner manager kitchen cashier"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+}
+
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" validate:"required,min=8,max=72"`
+}
+
+// CategoryRequest creates a category or replaces all of its fields.
+type CategoryRequest struct {
+	Name        string `json:"name" validate:"required,min=3,max=50"`
+	Description string `json:"description" validate:"omitempty,max=255"`
+	Position    int    `json:"position" validate:"min=0"`
+}
@@ -1,7 +1,20 @@
 package web
 
+// Response is the envelope for every API reply. Error is a stable
+// machine-readable code and is only set on failures.
 type Response[T any] struct {
 	Code   int    `json:"code"`
 	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 	Data   T      `json:"data,omitempty"`
+	Meta   *Meta  `json:"meta,omitempty"`
+}
+
+// Meta describes the page returned by a list endpoint. Page is only set for
+// offset pagination; NextCursor is empty on the last page.
+type Meta struct {
+	Total      int    `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
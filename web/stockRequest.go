@@ -0,0 +1,41 @@
+package web
+
+import (
+	"catering-admin-go/domain"
+	"catering-admin-go/helper"
+	"strconv"
+)
+
+// StockMovementRequest records a manual stock change. Waste always takes
+// stock away, so its delta must be negative.
+type StockMovementRequest struct {
+	Kind   string `json:"kind" validate:"required,oneof=adjustment waste"`
+	Delta  int    `json:"delta" validate:"required,ne=0"`
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type StockMovementQuery struct {
+	PageSize int    `query:"page_size" validate:"omitempty,min=1,max=100"`
+	Cursor   string `query:"cursor"`
+}
+
+func (q *StockMovementQuery) ToFilter(productId string) (*domain.StockMovementFilter, error) {
+	filter := &domain.StockMovementFilter{ProductId: productId, Limit: q.PageSize}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPageSize
+	}
+
+	if q.Cursor != "" {
+		cursor, err := helper.DecodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// Movements are paged by their numeric id.
+		if _, err := strconv.ParseInt(cursor.Id, 10, 64); err != nil {
+			return nil, helper.ErrInvalidCursor
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
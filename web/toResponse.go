@@ -1,7 +1,10 @@
 package web
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
 func SuccessResponse[T any](c *fiber.Ctx, code int, status string, data T) error {
@@ -12,10 +15,26 @@ func SuccessResponse[T any](c *fiber.Ctx, code int, status string, data T) error
 	})
 }
 
+func SuccessResponseWithMeta[T any](c *fiber.Ctx, code int, status string, data T, meta *Meta) error {
+	return c.Status(code).JSON(&Response[T]{
+		Code:   code,
+		Status: status,
+		Data:   data,
+		Meta:   meta,
+	})
+}
+
 func ErrorResponse(c *fiber.Ctx, code int, status string, message string) error {
 	return c.Status(code).JSON(&Response[any]{
 		Code:   code,
 		Status: status,
+		Error:  StatusErrorCode(code),
 		Data:   message,
 	})
 }
+
+// StatusErrorCode is the error code used when nothing more specific is known,
+// e.g. "not_found" for 404.
+func StatusErrorCode(code int) string {
+	return strings.ToLower(strings.ReplaceAll(utils.StatusMessage(code), " ", "_"))
+}
@@ -3,3 +3,11 @@ package web
 type Token struct {
 	AccessToken string `json:"access_token"`
 }
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
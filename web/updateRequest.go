@@ -1,14 +1,31 @@
 package web
 
 import (
-	"time"
+	"catering-admin-go/domain"
 )
 
+// UpdateProductRequest is a partial update: fields left out of the body stay
+// nil and keep their current value. A category_id of 0 removes the product
+// from its category. A new stock is recorded as an adjustment, with
+// StockReason as its reason.
 type UpdateProductRequest struct {
-	Name        *string    `json:"name" validate:"omitempty,alpha,min=5,max=50"`
-	Description *string    `json:"description" validate:"omitempty,alphanum"`
-	Stock       *int       `json:"stock" validate:"omitempty,number"`
-	Price       *int       `json:"price" validate:"omitempty,number"`
-	CreatedAt   *time.Time `json:"created_at"`
-	ModifiedAt  *time.Time `json:"modified_at"`
+	Name        *string `json:"name" form:"name" validate:"omitempty,alpha,min=5,max=50"`
+	Description *string `json:"description" form:"description" validate:"omitempty,alphanum"`
+	Stock       *int    `json:"stock" form:"stock" validate:"omitempty,number,min=0"`
+	StockReason string  `json:"stock_reason" form:"stock_reason" validate:"omitempty,max=255"`
+	Price       *int    `json:"price" form:"price" validate:"omitempty,number"`
+	CategoryId  *int    `json:"category_id" form:"category_id" validate:"omitempty,min=0"`
+}
+
+func (r *UpdateProductRequest) Empty() bool {
+	return r.Name == nil && r.Description == nil && r.Stock == nil && r.Price == nil && r.CategoryId == nil
+}
+
+func (r *UpdateProductRequest) ToPatch() *domain.ProductPatch {
+	return &domain.ProductPatch{
+		Name:        r.Name,
+		Description: r.Description,
+		Price:       r.Price,
+		CategoryId:  r.CategoryId,
+	}
 }
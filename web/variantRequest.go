@@ -0,0 +1,26 @@
+package web
+
+// VariantRequest creates a product variant or replaces all of its fields.
+// PriceDelta may be negative for portions cheaper than the base product.
+type VariantRequest struct {
+	Name       string `json:"name" validate:"required,max=50"`
+	PriceDelta int    `json:"price_delta"`
+	Stock      *int   `json:"stock" validate:"omitempty,min=0"`
+	Position   int    `json:"position" validate:"min=0"`
+}
+
+// OptionGroupRequest creates an option group or replaces its fields; the
+// group's options are managed separately.
+type OptionGroupRequest struct {
+	Name      string `json:"name" validate:"required,max=50"`
+	MinSelect int    `json:"min_select" validate:"min=0"`
+	MaxSelect int    `json:"max_select" validate:"min=0"`
+	Position  int    `json:"position" validate:"min=0"`
+}
+
+type OptionRequest struct {
+	Name       string `json:"name" validate:"required,max=50"`
+	PriceDelta int    `json:"price_delta"`
+	Stock      *int   `json:"stock" validate:"omitempty,min=0"`
+	Position   int    `json:"position" validate:"min=0"`
+}
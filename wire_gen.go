@@ -9,28 +9,44 @@ package main
 import (
 	"catering-admin-go/controller"
 	"catering-admin-go/helper"
+	"catering-admin-go/middleware"
 	"catering-admin-go/repository"
 	"catering-admin-go/service"
-	"github.com/gofiber/fiber/v2"
+	"catering-admin-go/storage"
 	"github.com/google/wire"
 )
 
 // Injectors from injector.go:
 
-func InitServer() (*fiber.App, func(), error) {
+func InitServer() (*Server, func(), error) {
 	repositoryRepository := repository.NewRepositoryImpl()
 	db, cleanup, err := helper.NewDb()
 	if err != nil {
 		return nil, nil, err
 	}
-	serviceService := service.NewServiceImpl(repositoryRepository, db)
+	storageStorage, err := storage.NewLocalStorage()
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	serviceService := service.NewServiceImpl(repositoryRepository, db, storageStorage)
 	controllerController := controller.NewControllerImpl(serviceService)
-	app := NewServer(controllerController)
-	return app, func() {
+	middlewareMiddleware := middleware.NewMiddlewareImpl(serviceService)
+	app := NewServer(controllerController, middlewareMiddleware, storageStorage)
+	trashPurger, err := service.NewTrashPurger(serviceService)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	server := &Server{
+		App:    app,
+		Purger: trashPurger,
+	}
+	return server, func() {
 		cleanup()
 	}, nil
 }
 
 // injector.go:
 
-var ServerSet = wire.NewSet(repository.NewRepositoryImpl, service.NewServiceImpl, controller.NewControllerImpl, helper.NewDb, NewServer)
+var ServerSet = wire.NewSet(repository.NewRepositoryImpl, service.NewServiceImpl, service.NewTrashPurger, storage.NewLocalStorage, controller.NewControllerImpl, middleware.NewMiddlewareImpl, helper.NewDb, NewServer, wire.Struct(new(Server), "*"))